@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package core holds the types shared by every genkit transport (HTTP,
+// gRPC): actions, errors, and the per-request context flows run with.
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// StatusName is a flow error classification, named after the equivalent
+// gRPC status code.
+type StatusName string
+
+const (
+	CANCELLED           StatusName = "CANCELLED"
+	UNKNOWN             StatusName = "UNKNOWN"
+	INVALID_ARGUMENT    StatusName = "INVALID_ARGUMENT"
+	DEADLINE_EXCEEDED   StatusName = "DEADLINE_EXCEEDED"
+	NOT_FOUND           StatusName = "NOT_FOUND"
+	ALREADY_EXISTS      StatusName = "ALREADY_EXISTS"
+	PERMISSION_DENIED   StatusName = "PERMISSION_DENIED"
+	RESOURCE_EXHAUSTED  StatusName = "RESOURCE_EXHAUSTED"
+	FAILED_PRECONDITION StatusName = "FAILED_PRECONDITION"
+	ABORTED             StatusName = "ABORTED"
+	OUT_OF_RANGE        StatusName = "OUT_OF_RANGE"
+	UNIMPLEMENTED       StatusName = "UNIMPLEMENTED"
+	INTERNAL            StatusName = "INTERNAL"
+	UNAVAILABLE         StatusName = "UNAVAILABLE"
+	UNAUTHENTICATED     StatusName = "UNAUTHENTICATED"
+)
+
+// httpStatus maps a StatusName to the HTTP status code Handler/HandlerFunc
+// respond with.
+var httpStatus = map[StatusName]int{
+	INVALID_ARGUMENT:    400,
+	UNAUTHENTICATED:     401,
+	PERMISSION_DENIED:   403,
+	NOT_FOUND:           404,
+	ALREADY_EXISTS:      409,
+	ABORTED:             409,
+	FAILED_PRECONDITION: 400,
+	OUT_OF_RANGE:        400,
+	RESOURCE_EXHAUSTED:  429,
+	CANCELLED:           499,
+	DEADLINE_EXCEEDED:   504,
+	UNIMPLEMENTED:       501,
+	UNAVAILABLE:         503,
+}
+
+// HTTPStatus returns the HTTP status code s maps to, defaulting to 500 for
+// anything not in the table above (including INTERNAL and UNKNOWN).
+func (s StatusName) HTTPStatus() int {
+	if code, ok := httpStatus[s]; ok {
+		return code
+	}
+	return 500
+}
+
+// GenkitError is the structured error type a flow returns to signal a
+// specific failure classification; Handler/HandlerFunc/GRPCServer map
+// Status to the equivalent HTTP status or gRPC code.
+type GenkitError struct {
+	Status  StatusName
+	Message string
+	Details any
+}
+
+func (e *GenkitError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Message)
+}
+
+// NewError returns a *GenkitError with the given status and a message
+// formatted like fmt.Sprintf.
+func NewError(status StatusName, format string, args ...any) *GenkitError {
+	return &GenkitError{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// PublicError wraps a message that's safe to return to an HTTP/gRPC caller
+// even when the underlying failure shouldn't be. Unlike GenkitError, its
+// Status isn't mapped to a specific response code: it always surfaces as a
+// generic internal error, only with a caller-safe message instead of
+// whatever unsanitized error text a flow happened to return.
+type PublicError struct {
+	Status  StatusName
+	Message string
+	Details any
+}
+
+func (e *PublicError) Error() string { return e.Message }
+
+// NewPublicError returns a *PublicError. status is recorded for logging but
+// does not change how the error is reported to callers.
+func NewPublicError(status StatusName, message string, details any) *PublicError {
+	return &PublicError{Status: status, Message: message, Details: details}
+}
+
+// ActionContext is out-of-band data threaded alongside a flow's input, most
+// commonly auth claims populated by a ContextProvider.
+type ActionContext map[string]any
+
+// RequestData is the transport-agnostic view of an inbound request passed to
+// a ContextProvider: an HTTP request's headers/method, or a gRPC call's
+// metadata reshaped the same way.
+type RequestData struct {
+	Method  string
+	Headers map[string][]string
+	Input   json.RawMessage
+}
+
+// ContextProvider derives an ActionContext from an inbound request, most
+// commonly by validating an auth header. Returning an error fails the
+// request before the flow runs.
+type ContextProvider func(ctx context.Context, req RequestData) (ActionContext, error)
+
+type actionContextKey struct{}
+
+// WithActionContext returns a context carrying ac, retrievable with
+// FromContext.
+func WithActionContext(ctx context.Context, ac ActionContext) context.Context {
+	return context.WithValue(ctx, actionContextKey{}, ac)
+}
+
+// FromContext returns the ActionContext attached to ctx, or nil if none was
+// attached.
+func FromContext(ctx context.Context) ActionContext {
+	ac, _ := ctx.Value(actionContextKey{}).(ActionContext)
+	return ac
+}
+
+// Action is anything that can be registered with Genkit and run generically
+// by name, over JSON, regardless of its real input/output types: an HTTP
+// Handler, GRPCServer, or the development UI all call RunJSON rather than a
+// type-specific method.
+type Action interface {
+	// Name is the name the action was registered under.
+	Name() string
+
+	// RunJSON decodes input, runs the action, and returns its JSON-encoded
+	// output. If the action is a streaming flow and cb is non-nil, cb is
+	// called with each JSON-encoded intermediate chunk as it's produced.
+	RunJSON(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error)
+}
+
+// StreamingFlowValue is one value produced while iterating a streaming
+// flow's result: either an intermediate Stream chunk, or the final Output
+// once Done is true.
+type StreamingFlowValue[S, O any] struct {
+	Stream S
+	Output O
+	Done   bool
+}
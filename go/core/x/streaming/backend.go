@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Backend persists stream chunks and terminal state outside the serving
+// process, so a BackedStreamManager can be shared by every replica of an
+// HTTP server behind a load balancer: a reconnect routed to a different pod
+// than the one that produced the stream can still replay it.
+//
+// Implementations live in their own subpackages (e.g. streaming/redis,
+// streaming/postgres) to keep backend-specific client dependencies out of
+// the core streaming package.
+type Backend interface {
+	// AppendChunk stores chunk for streamID. The backend is responsible for
+	// assigning chunk.ID, which must be strictly increasing per streamID.
+	AppendChunk(ctx context.Context, streamID string, data json.RawMessage) (Chunk, error)
+
+	// LoadChunks returns every chunk stored for streamID with an ID greater
+	// than afterID, oldest first.
+	LoadChunks(ctx context.Context, streamID string, afterID uint64) ([]Chunk, error)
+
+	// MarkDone records the terminal result or failure for streamID. Exactly
+	// one of result and failure is non-nil.
+	MarkDone(ctx context.Context, streamID string, result *json.RawMessage, failure *Failure) error
+
+	// LoadState returns whether streamID has finished, and if so its
+	// terminal result or failure. ok is false if streamID is unknown to the
+	// backend (e.g. it expired).
+	LoadState(ctx context.Context, streamID string) (done bool, result *json.RawMessage, failure *Failure, ok bool, err error)
+
+	// SetTTL bounds how long streamID's state is retained after it was last
+	// written to. BackedStreamManager calls it when a stream is created and
+	// again after every appended chunk; MarkDone does not refresh it.
+	SetTTL(ctx context.Context, streamID string, ttl time.Duration) error
+
+	// Subscribe fans out chunks appended to streamID after subscription,
+	// for replicas other than the one currently producing them. The
+	// returned channel is closed, and the unsubscribe func is safe to call
+	// multiple times, once the stream is marked done or the context is
+	// canceled.
+	Subscribe(ctx context.Context, streamID string) (chunks <-chan Chunk, unsubscribe func(), err error)
+
+	// Close releases resources held by the backend (e.g. a connection pool).
+	Close() error
+}
+
+// BackedStreamManagerOption configures a BackedStreamManager.
+type BackedStreamManagerOption func(*backedStreamManagerConfig)
+
+type backedStreamManagerConfig struct {
+	ttl               time.Duration
+	maxBufferedChunks int
+}
+
+// WithTTL sets how long a stream's state is retained by the backend after
+// it was last appended to. The default is 24 hours.
+func WithTTL(ttl time.Duration) BackedStreamManagerOption {
+	return func(c *backedStreamManagerConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithBackedMaxBufferedChunks caps how many chunks Load/ReadFrom ever
+// return for a single stream, trimming from the oldest end, independent of
+// how many the backend itself retains.
+func WithBackedMaxBufferedChunks(n int) BackedStreamManagerOption {
+	return func(c *backedStreamManagerConfig) {
+		c.maxBufferedChunks = n
+	}
+}
+
+// BackedStreamManager is a StreamManager backed by a pluggable persistent
+// Backend, so it can be shared across replicas of an HTTP server.
+type BackedStreamManager struct {
+	backend Backend
+	cfg     backedStreamManagerConfig
+}
+
+// NewBackedStreamManager returns a StreamManager that stores stream state in
+// backend instead of in process memory.
+func NewBackedStreamManager(backend Backend, opts ...BackedStreamManagerOption) *BackedStreamManager {
+	cfg := backedStreamManagerConfig{
+		ttl:               24 * time.Hour,
+		maxBufferedChunks: defaultMaxBufferedChunks,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &BackedStreamManager{backend: backend, cfg: cfg}
+}
+
+func (m *BackedStreamManager) Create() (string, error) {
+	ctx := context.Background()
+	id, err := newStreamID()
+	if err != nil {
+		return "", err
+	}
+	if err := m.backend.SetTTL(ctx, id, m.cfg.ttl); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (m *BackedStreamManager) Append(streamID string, data json.RawMessage) error {
+	ctx := context.Background()
+	if _, err := m.backend.AppendChunk(ctx, streamID, data); err != nil {
+		return err
+	}
+	return m.backend.SetTTL(ctx, streamID, m.cfg.ttl)
+}
+
+func (m *BackedStreamManager) Complete(streamID string, result json.RawMessage) error {
+	return m.backend.MarkDone(context.Background(), streamID, &result, nil)
+}
+
+func (m *BackedStreamManager) Fail(streamID string, failure Failure) error {
+	return m.backend.MarkDone(context.Background(), streamID, nil, &failure)
+}
+
+func (m *BackedStreamManager) Load(streamID string) ([]Chunk, *json.RawMessage, *Failure, bool, bool) {
+	return m.ReadFrom(streamID, 0)
+}
+
+func (m *BackedStreamManager) ReadFrom(streamID string, afterID uint64) ([]Chunk, *json.RawMessage, *Failure, bool, bool) {
+	ctx := context.Background()
+	done, result, failure, ok, err := m.backend.LoadState(ctx, streamID)
+	if err != nil || !ok {
+		return nil, nil, nil, false, false
+	}
+	chunks, err := m.backend.LoadChunks(ctx, streamID, afterID)
+	if err != nil {
+		return nil, nil, nil, false, false
+	}
+	if m.cfg.maxBufferedChunks > 0 && len(chunks) > m.cfg.maxBufferedChunks {
+		chunks = chunks[len(chunks)-m.cfg.maxBufferedChunks:]
+	}
+	return chunks, result, failure, done, true
+}
+
+func (m *BackedStreamManager) Close() error {
+	return m.backend.Close()
+}
+
+// Subscribe exposes the backend's live pub/sub fan-out directly, for an SSE
+// handler to tail a stream being produced by another replica while it also
+// serves the already-buffered chunks via ReadFrom.
+func (m *BackedStreamManager) Subscribe(ctx context.Context, streamID string) (<-chan Chunk, func(), error) {
+	return m.backend.Subscribe(ctx, streamID)
+}
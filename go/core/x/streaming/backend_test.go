@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBackend is a minimal in-memory Backend used to test BackedStreamManager
+// without pulling in a real Redis/PostgreSQL dependency.
+type fakeBackend struct {
+	mu      sync.Mutex
+	chunks  map[string][]Chunk
+	nextID  map[string]uint64
+	done    map[string]bool
+	result  map[string]*json.RawMessage
+	failure map[string]*Failure
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		chunks:  map[string][]Chunk{},
+		nextID:  map[string]uint64{},
+		done:    map[string]bool{},
+		result:  map[string]*json.RawMessage{},
+		failure: map[string]*Failure{},
+	}
+}
+
+func (f *fakeBackend) AppendChunk(ctx context.Context, streamID string, data json.RawMessage) (Chunk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID[streamID]++
+	chunk := Chunk{ID: f.nextID[streamID], Data: data}
+	f.chunks[streamID] = append(f.chunks[streamID], chunk)
+	return chunk, nil
+}
+
+func (f *fakeBackend) LoadChunks(ctx context.Context, streamID string, afterID uint64) ([]Chunk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []Chunk
+	for _, c := range f.chunks[streamID] {
+		if c.ID > afterID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeBackend) MarkDone(ctx context.Context, streamID string, result *json.RawMessage, failure *Failure) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.done[streamID] = true
+	f.result[streamID] = result
+	f.failure[streamID] = failure
+	return nil
+}
+
+func (f *fakeBackend) LoadState(ctx context.Context, streamID string) (bool, *json.RawMessage, *Failure, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nextID[streamID]; !ok {
+		return false, nil, nil, false, nil
+	}
+	return f.done[streamID], f.result[streamID], f.failure[streamID], true, nil
+}
+
+func (f *fakeBackend) SetTTL(ctx context.Context, streamID string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nextID[streamID]; !ok {
+		f.nextID[streamID] = 0
+	}
+	return nil
+}
+
+func (f *fakeBackend) Subscribe(ctx context.Context, streamID string) (<-chan Chunk, func(), error) {
+	ch := make(chan Chunk)
+	close(ch)
+	return ch, func() {}, nil
+}
+
+func (f *fakeBackend) Close() error { return nil }
+
+func TestBackedStreamManager(t *testing.T) {
+	m := NewBackedStreamManager(newFakeBackend())
+	defer m.Close()
+
+	id, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Append(id, json.RawMessage(`"a"`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Append(id, json.RawMessage(`"b"`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Complete(id, json.RawMessage(`"done"`)); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, result, failure, done, ok := m.Load(id)
+	if !ok {
+		t.Fatal("want ok=true for known stream")
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("want 2 chunks, got %d", len(chunks))
+	}
+	if failure != nil {
+		t.Errorf("want nil failure, got %v", failure)
+	}
+	if !done || result == nil || string(*result) != `"done"` {
+		t.Errorf("want done with result %q, got done=%v result=%v", `"done"`, done, result)
+	}
+
+	chunks, _, _, _, ok = m.ReadFrom(id, chunks[0].ID)
+	if !ok {
+		t.Fatal("want ok=true for known stream")
+	}
+	if len(chunks) != 1 || string(chunks[0].Data) != `"b"` {
+		t.Errorf("want only chunk after the first ID, got %+v", chunks)
+	}
+}
+
+func TestBackedStreamManager_UnknownStream(t *testing.T) {
+	m := NewBackedStreamManager(newFakeBackend())
+	defer m.Close()
+
+	if _, _, _, _, ok := m.Load("does-not-exist"); ok {
+		t.Error("want ok=false for unknown stream")
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package streaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// newStreamID generates the opaque ID used to identify a stream, shared by
+// every StreamManager implementation so stream IDs look the same regardless
+// of which backend produced them.
+func newStreamID() (string, error) {
+	return uuid.NewString(), nil
+}
+
+// defaultMaxBufferedChunks bounds how many chunks the in-memory manager
+// keeps per stream before evicting the oldest ones, so a long-running
+// streaming flow can't grow a single stream's buffer without bound.
+const defaultMaxBufferedChunks = 1000
+
+// InMemoryStreamManagerOption configures a InMemoryStreamManager.
+type InMemoryStreamManagerOption func(*InMemoryStreamManager)
+
+// WithMaxBufferedChunks sets the maximum number of chunks retained per
+// stream. Once exceeded, the oldest chunks are evicted first (the ring
+// buffer always keeps the most recent n). The terminal result/error chunk
+// is never evicted.
+func WithMaxBufferedChunks(n int) InMemoryStreamManagerOption {
+	return func(m *InMemoryStreamManager) {
+		m.maxBufferedChunks = n
+	}
+}
+
+// stream holds the buffered state for a single in-flight or completed
+// stream.
+type stream struct {
+	mu      sync.Mutex
+	chunks  []Chunk
+	nextID  uint64
+	result  *json.RawMessage
+	failure *Failure
+	done    bool
+}
+
+// InMemoryStreamManager is a StreamManager backed by an in-process map. It
+// does not survive a process restart and can't be shared across replicas;
+// it's meant for single-instance deployments and tests.
+type InMemoryStreamManager struct {
+	maxBufferedChunks int
+
+	mu      sync.Mutex
+	streams map[string]*stream
+}
+
+// NewInMemoryStreamManager returns a StreamManager that buffers stream
+// chunks in process memory.
+func NewInMemoryStreamManager(opts ...InMemoryStreamManagerOption) *InMemoryStreamManager {
+	m := &InMemoryStreamManager{
+		maxBufferedChunks: defaultMaxBufferedChunks,
+		streams:           map[string]*stream{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *InMemoryStreamManager) Create() (string, error) {
+	id, err := newStreamID()
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	m.streams[id] = &stream{}
+	m.mu.Unlock()
+	return id, nil
+}
+
+func (m *InMemoryStreamManager) get(streamID string) (*stream, bool) {
+	m.mu.Lock()
+	s, ok := m.streams[streamID]
+	m.mu.Unlock()
+	return s, ok
+}
+
+func (m *InMemoryStreamManager) Append(streamID string, data json.RawMessage) error {
+	s, ok := m.get(streamID)
+	if !ok {
+		return fmt.Errorf("streaming: unknown stream %q", streamID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.chunks = append(s.chunks, Chunk{ID: s.nextID, Data: data})
+	if m.maxBufferedChunks > 0 && len(s.chunks) > m.maxBufferedChunks {
+		s.chunks = s.chunks[len(s.chunks)-m.maxBufferedChunks:]
+	}
+	return nil
+}
+
+func (m *InMemoryStreamManager) Complete(streamID string, result json.RawMessage) error {
+	s, ok := m.get(streamID)
+	if !ok {
+		return fmt.Errorf("streaming: unknown stream %q", streamID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result = &result
+	s.done = true
+	return nil
+}
+
+func (m *InMemoryStreamManager) Fail(streamID string, failure Failure) error {
+	s, ok := m.get(streamID)
+	if !ok {
+		return fmt.Errorf("streaming: unknown stream %q", streamID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failure = &failure
+	s.done = true
+	return nil
+}
+
+func (m *InMemoryStreamManager) Load(streamID string) ([]Chunk, *json.RawMessage, *Failure, bool, bool) {
+	return m.ReadFrom(streamID, 0)
+}
+
+func (m *InMemoryStreamManager) ReadFrom(streamID string, afterID uint64) ([]Chunk, *json.RawMessage, *Failure, bool, bool) {
+	s, ok := m.get(streamID)
+	if !ok {
+		return nil, nil, nil, false, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var chunks []Chunk
+	for _, c := range s.chunks {
+		if c.ID > afterID {
+			chunks = append(chunks, c)
+		}
+	}
+	return chunks, s.result, s.failure, s.done, true
+}
+
+func (m *InMemoryStreamManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streams = map[string]*stream{}
+	return nil
+}
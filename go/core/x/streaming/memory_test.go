@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package streaming
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReadFrom(t *testing.T) {
+	m := NewInMemoryStreamManager()
+	defer m.Close()
+
+	id, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []string{"a", "b", "c"} {
+		if err := m.Append(id, json.RawMessage(`"`+c+`"`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Complete(id, json.RawMessage(`"done"`)); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, result, failure, done, ok := m.ReadFrom(id, 0)
+	if !ok {
+		t.Fatal("want ok=true for known stream")
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("want 3 chunks, got %d", len(chunks))
+	}
+	if failure != nil {
+		t.Errorf("want nil failure, got %v", failure)
+	}
+	if !done || result == nil || string(*result) != `"done"` {
+		t.Errorf("want done with result %q, got done=%v result=%v", `"done"`, done, result)
+	}
+
+	chunks, _, _, _, ok = m.ReadFrom(id, chunks[1].ID)
+	if !ok {
+		t.Fatal("want ok=true for known stream")
+	}
+	if len(chunks) != 1 || string(chunks[0].Data) != `"c"` {
+		t.Errorf("want only chunk after the second ID, got %+v", chunks)
+	}
+}
+
+func TestReadFrom_UnknownStream(t *testing.T) {
+	m := NewInMemoryStreamManager()
+	defer m.Close()
+
+	if _, _, _, _, ok := m.ReadFrom("does-not-exist", 0); ok {
+		t.Error("want ok=false for unknown stream")
+	}
+}
+
+func TestMaxBufferedChunks(t *testing.T) {
+	m := NewInMemoryStreamManager(WithMaxBufferedChunks(2))
+	defer m.Close()
+
+	id, err := m.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []string{"a", "b", "c"} {
+		if err := m.Append(id, json.RawMessage(`"`+c+`"`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chunks, _, _, _, ok := m.ReadFrom(id, 0)
+	if !ok {
+		t.Fatal("want ok=true for known stream")
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("want ring buffer to retain only 2 chunks, got %d", len(chunks))
+	}
+	if string(chunks[0].Data) != `"b"` || string(chunks[1].Data) != `"c"` {
+		t.Errorf("want the two most recent chunks retained, got %+v", chunks)
+	}
+	// IDs stay monotonic even though earlier chunks were evicted.
+	if chunks[0].ID != 2 || chunks[1].ID != 3 {
+		t.Errorf("want IDs 2 and 3 preserved across eviction, got %d and %d", chunks[0].ID, chunks[1].ID)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package postgres is a streaming.Backend backed by PostgreSQL: chunks and
+// terminal state are stored in tables created by Migrate, and live fan-out
+// uses LISTEN/NOTIFY so a subscriber on one replica sees chunks appended by
+// a producer on another.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/firebase/genkit/go/core/x/streaming"
+)
+
+// Migrate is the SQL that creates the tables Backend needs, if they don't
+// already exist. Execute it once at startup; it's safe to execute repeatedly.
+const Migrate = `
+CREATE TABLE IF NOT EXISTS genkit_stream_chunks (
+	stream_id  text   NOT NULL,
+	id         bigint NOT NULL,
+	data       jsonb  NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (stream_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS genkit_stream_state (
+	stream_id  text PRIMARY KEY,
+	next_id    bigint NOT NULL DEFAULT 0,
+	done       boolean NOT NULL DEFAULT false,
+	result     jsonb,
+	failure    jsonb,
+	expires_at timestamptz NOT NULL
+);
+`
+
+// Backend implements streaming.Backend on top of a PostgreSQL connection
+// pool.
+type Backend struct {
+	pool *pgxpool.Pool
+}
+
+// NewBackend returns a streaming.Backend that stores stream state in
+// PostgreSQL via pool. The caller must have run Migrate against the same
+// database beforehand.
+func NewBackend(pool *pgxpool.Pool) *Backend {
+	return &Backend{pool: pool}
+}
+
+func (b *Backend) AppendChunk(ctx context.Context, streamID string, data json.RawMessage) (streaming.Chunk, error) {
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		return streaming.Chunk{}, fmt.Errorf("postgres: starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var nextID int64
+	err = tx.QueryRow(ctx,
+		`UPDATE genkit_stream_state SET next_id = next_id + 1 WHERE stream_id = $1 RETURNING next_id`,
+		streamID,
+	).Scan(&nextID)
+	if err != nil {
+		return streaming.Chunk{}, fmt.Errorf("postgres: incrementing chunk id: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO genkit_stream_chunks (stream_id, id, data) VALUES ($1, $2, $3)`,
+		streamID, nextID, data,
+	); err != nil {
+		return streaming.Chunk{}, fmt.Errorf("postgres: inserting chunk: %w", err)
+	}
+
+	payload, err := json.Marshal(notification{StreamID: streamID, ID: uint64(nextID), Data: data})
+	if err != nil {
+		return streaming.Chunk{}, err
+	}
+	if _, err := tx.Exec(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, string(payload)); err != nil {
+		return streaming.Chunk{}, fmt.Errorf("postgres: notifying subscribers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return streaming.Chunk{}, fmt.Errorf("postgres: committing chunk: %w", err)
+	}
+	return streaming.Chunk{ID: uint64(nextID), Data: data}, nil
+}
+
+func (b *Backend) LoadChunks(ctx context.Context, streamID string, afterID uint64) ([]streaming.Chunk, error) {
+	rows, err := b.pool.Query(ctx,
+		`SELECT id, data FROM genkit_stream_chunks WHERE stream_id = $1 AND id > $2 ORDER BY id ASC`,
+		streamID, afterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: loading chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []streaming.Chunk
+	for rows.Next() {
+		var c streaming.Chunk
+		if err := rows.Scan(&c.ID, &c.Data); err != nil {
+			return nil, fmt.Errorf("postgres: scanning chunk: %w", err)
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, rows.Err()
+}
+
+func (b *Backend) MarkDone(ctx context.Context, streamID string, result *json.RawMessage, failure *streaming.Failure) error {
+	var failureJSON []byte
+	if failure != nil {
+		var err error
+		failureJSON, err = json.Marshal(failure)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := b.pool.Exec(ctx,
+		`UPDATE genkit_stream_state SET done = true, result = $2, failure = $3 WHERE stream_id = $1`,
+		streamID, result, failureJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: marking stream done: %w", err)
+	}
+	return nil
+}
+
+func (b *Backend) LoadState(ctx context.Context, streamID string) (done bool, result *json.RawMessage, failure *streaming.Failure, ok bool, err error) {
+	var failureJSON []byte
+	err = b.pool.QueryRow(ctx,
+		`SELECT done, result, failure FROM genkit_stream_state WHERE stream_id = $1 AND expires_at > now()`,
+		streamID,
+	).Scan(&done, &result, &failureJSON)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return false, nil, nil, false, nil
+		}
+		return false, nil, nil, false, fmt.Errorf("postgres: loading stream state: %w", err)
+	}
+	if len(failureJSON) > 0 {
+		var f streaming.Failure
+		if err := json.Unmarshal(failureJSON, &f); err != nil {
+			return false, nil, nil, false, fmt.Errorf("postgres: decoding failure: %w", err)
+		}
+		failure = &f
+	}
+	return done, result, failure, true, nil
+}
+
+func (b *Backend) SetTTL(ctx context.Context, streamID string, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl)
+	_, err := b.pool.Exec(ctx,
+		`INSERT INTO genkit_stream_state (stream_id, expires_at) VALUES ($1, $2)
+		 ON CONFLICT (stream_id) DO UPDATE SET expires_at = $2`,
+		streamID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: setting TTL: %w", err)
+	}
+	return nil
+}
+
+const notifyChannel = "genkit_stream_chunks"
+
+type notification struct {
+	StreamID string          `json:"stream_id"`
+	ID       uint64          `json:"id"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func (b *Backend) Subscribe(ctx context.Context, streamID string) (<-chan streaming.Chunk, func(), error) {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("postgres: acquiring listen connection: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		conn.Release()
+		return nil, nil, fmt.Errorf("postgres: listening: %w", err)
+	}
+
+	out := make(chan streaming.Chunk)
+	unsubscribe := func() { conn.Release() }
+
+	go func() {
+		defer close(out)
+		for {
+			notif, err := conn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				return
+			}
+			var n notification
+			if err := json.Unmarshal([]byte(notif.Payload), &n); err != nil || n.StreamID != streamID {
+				continue
+			}
+			select {
+			case out <- streaming.Chunk{ID: n.ID, Data: n.Data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, unsubscribe, nil
+}
+
+func (b *Backend) Close() error {
+	b.pool.Close()
+	return nil
+}
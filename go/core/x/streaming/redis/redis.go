@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis is a streaming.Backend backed by Redis: chunks are stored in
+// a per-stream list, terminal state in a per-stream hash, and live fan-out
+// uses Redis pub/sub so a subscriber on one replica sees chunks appended by
+// a producer on another.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/firebase/genkit/go/core/x/streaming"
+)
+
+const keyPrefix = "genkit:stream:"
+
+// Backend implements streaming.Backend on top of a Redis client.
+type Backend struct {
+	client *redis.Client
+}
+
+// NewBackend returns a streaming.Backend that stores stream state in Redis
+// via client.
+func NewBackend(client *redis.Client) *Backend {
+	return &Backend{client: client}
+}
+
+func chunksKey(streamID string) string { return keyPrefix + streamID + ":chunks" }
+func stateKey(streamID string) string  { return keyPrefix + streamID + ":state" }
+func channelName(streamID string) string { return keyPrefix + streamID + ":pubsub" }
+
+// chunkRecord is the JSON shape stored per list element in chunksKey.
+type chunkRecord struct {
+	ID   uint64          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (b *Backend) AppendChunk(ctx context.Context, streamID string, data json.RawMessage) (streaming.Chunk, error) {
+	id, err := b.client.HIncrBy(ctx, stateKey(streamID), "next_id", 1).Result()
+	if err != nil {
+		return streaming.Chunk{}, fmt.Errorf("redis: incrementing chunk id: %w", err)
+	}
+	chunk := streaming.Chunk{ID: uint64(id), Data: data}
+
+	raw, err := json.Marshal(chunkRecord{ID: chunk.ID, Data: data})
+	if err != nil {
+		return streaming.Chunk{}, err
+	}
+	if err := b.client.RPush(ctx, chunksKey(streamID), raw).Err(); err != nil {
+		return streaming.Chunk{}, fmt.Errorf("redis: appending chunk: %w", err)
+	}
+	if err := b.client.Publish(ctx, channelName(streamID), raw).Err(); err != nil {
+		return streaming.Chunk{}, fmt.Errorf("redis: publishing chunk: %w", err)
+	}
+	return chunk, nil
+}
+
+func (b *Backend) LoadChunks(ctx context.Context, streamID string, afterID uint64) ([]streaming.Chunk, error) {
+	raws, err := b.client.LRange(ctx, chunksKey(streamID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: loading chunks: %w", err)
+	}
+	var chunks []streaming.Chunk
+	for _, raw := range raws {
+		var rec chunkRecord
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			return nil, fmt.Errorf("redis: decoding chunk: %w", err)
+		}
+		if rec.ID > afterID {
+			chunks = append(chunks, streaming.Chunk{ID: rec.ID, Data: rec.Data})
+		}
+	}
+	return chunks, nil
+}
+
+func (b *Backend) MarkDone(ctx context.Context, streamID string, result *json.RawMessage, failure *streaming.Failure) error {
+	fields := map[string]any{"done": "1"}
+	if result != nil {
+		fields["result"] = string(*result)
+	}
+	if failure != nil {
+		raw, err := json.Marshal(failure)
+		if err != nil {
+			return err
+		}
+		fields["failure"] = string(raw)
+	}
+	if err := b.client.HSet(ctx, stateKey(streamID), fields).Err(); err != nil {
+		return fmt.Errorf("redis: marking stream done: %w", err)
+	}
+	return nil
+}
+
+func (b *Backend) LoadState(ctx context.Context, streamID string) (done bool, result *json.RawMessage, failure *streaming.Failure, ok bool, err error) {
+	vals, err := b.client.HGetAll(ctx, stateKey(streamID)).Result()
+	if err != nil {
+		return false, nil, nil, false, fmt.Errorf("redis: loading stream state: %w", err)
+	}
+	if len(vals) == 0 {
+		return false, nil, nil, false, nil
+	}
+	if r, ok := vals["result"]; ok {
+		raw := json.RawMessage(r)
+		result = &raw
+	}
+	if f, ok := vals["failure"]; ok {
+		var fail streaming.Failure
+		if err := json.Unmarshal([]byte(f), &fail); err != nil {
+			return false, nil, nil, false, fmt.Errorf("redis: decoding failure: %w", err)
+		}
+		failure = &fail
+	}
+	return vals["done"] == "1", result, failure, true, nil
+}
+
+// SetTTL is also what Create uses to make a stream's existence durable
+// before any chunk has been appended: EXPIRE is a no-op against a key that
+// doesn't exist yet, so without the HSetNX below a freshly created stream
+// would leave no trace in Redis and LoadState would report it unknown until
+// the first AppendChunk/MarkDone created stateKey implicitly.
+func (b *Backend) SetTTL(ctx context.Context, streamID string, ttl time.Duration) error {
+	state := stateKey(streamID)
+	if err := b.client.HSetNX(ctx, state, "created", "1").Err(); err != nil {
+		return fmt.Errorf("redis: initializing stream state: %w", err)
+	}
+	if err := b.client.Expire(ctx, state, ttl).Err(); err != nil {
+		return fmt.Errorf("redis: setting state TTL: %w", err)
+	}
+	if err := b.client.Expire(ctx, chunksKey(streamID), ttl).Err(); err != nil {
+		return fmt.Errorf("redis: setting chunks TTL: %w", err)
+	}
+	return nil
+}
+
+func (b *Backend) Subscribe(ctx context.Context, streamID string) (<-chan streaming.Chunk, func(), error) {
+	sub := b.client.Subscribe(ctx, channelName(streamID))
+	raws := sub.Channel()
+
+	out := make(chan streaming.Chunk)
+	go func() {
+		defer close(out)
+		for msg := range raws {
+			var rec chunkRecord
+			if err := json.Unmarshal([]byte(msg.Payload), &rec); err != nil {
+				continue
+			}
+			select {
+			case out <- streaming.Chunk{ID: rec.ID, Data: rec.Data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, func() { sub.Close() }, nil
+}
+
+func (b *Backend) Close() error {
+	return b.client.Close()
+}
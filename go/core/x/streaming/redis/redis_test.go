@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestBackend(t *testing.T) *Backend {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewBackend(client)
+}
+
+func TestSetTTL_PersistsStreamBeforeAnyChunk(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBackend(t)
+
+	if err := b.SetTTL(ctx, "stream-1", time.Hour); err != nil {
+		t.Fatalf("SetTTL: %v", err)
+	}
+
+	done, result, failure, ok, err := b.LoadState(ctx, "stream-1")
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if !ok {
+		t.Fatal("want LoadState to find the stream right after Create/SetTTL, got ok=false")
+	}
+	if done || result != nil || failure != nil {
+		t.Errorf("want a freshly created stream to be unfinished with no result/failure, got done=%v result=%v failure=%v", done, result, failure)
+	}
+}
+
+func TestAppendChunkThenLoadState(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBackend(t)
+
+	if err := b.SetTTL(ctx, "stream-1", time.Hour); err != nil {
+		t.Fatalf("SetTTL: %v", err)
+	}
+	if _, err := b.AppendChunk(ctx, "stream-1", []byte(`"a"`)); err != nil {
+		t.Fatalf("AppendChunk: %v", err)
+	}
+
+	chunks, err := b.LoadChunks(ctx, "stream-1", 0)
+	if err != nil {
+		t.Fatalf("LoadChunks: %v", err)
+	}
+	if len(chunks) != 1 || string(chunks[0].Data) != `"a"` {
+		t.Fatalf("want one chunk %q, got %+v", `"a"`, chunks)
+	}
+
+	_, _, _, ok, err := b.LoadState(ctx, "stream-1")
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if !ok {
+		t.Fatal("want stream to still be known after AppendChunk")
+	}
+}
+
+func TestLoadState_UnknownStream(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBackend(t)
+
+	_, _, _, ok, err := b.LoadState(ctx, "never-created")
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if ok {
+		t.Fatal("want ok=false for a stream that was never created")
+	}
+}
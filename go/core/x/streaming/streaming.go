@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package streaming lets an HTTP server durably replay a running or
+// completed flow's streaming chunks, so a client that drops connection
+// (WithStreamManager) can resubscribe with an `X-Genkit-Stream-Id` and pick
+// up where it left off instead of re-running the flow.
+package streaming
+
+import "encoding/json"
+
+// Chunk is one buffered piece of a stream: an intermediate callback
+// invocation. The terminal result/error is not a Chunk; a StreamManager
+// keeps it separately (see Complete and Fail). ID is monotonically increasing
+// within a stream, starting at 1, and is assigned when the chunk is
+// appended, so it also doubles as an SSE `Last-Event-ID` value.
+type Chunk struct {
+	ID   uint64
+	Data json.RawMessage
+}
+
+// Failure is the terminal state recorded when a flow run errors out partway
+// through a stream, carrying enough detail to reconstruct the same
+// `{"error":...}` envelope the original SSE response sent.
+type Failure struct {
+	Status  string
+	Message string
+	Details string
+}
+
+// StreamManager persists the chunks emitted by a running flow so that a
+// client can resubscribe to a stream identified by an opaque ID, after a
+// dropped connection, and replay everything it missed.
+type StreamManager interface {
+	// Create allocates a new stream and returns its ID.
+	Create() (streamID string, err error)
+
+	// Append records an intermediate chunk for streamID.
+	Append(streamID string, data json.RawMessage) error
+
+	// Complete marks streamID done with its final result.
+	Complete(streamID string, result json.RawMessage) error
+
+	// Fail marks streamID done with a terminal error.
+	Fail(streamID string, failure Failure) error
+
+	// Load returns every buffered chunk for streamID along with its terminal
+	// state, for a client resubscribing with no Last-Event-ID. ok is false
+	// if streamID is unknown (e.g. it was never created, or it has been
+	// evicted past its retention window).
+	Load(streamID string) (chunks []Chunk, result *json.RawMessage, failure *Failure, done bool, ok bool)
+
+	// ReadFrom returns the buffered chunks for streamID with an ID strictly
+	// greater than afterID, along with its terminal state, for a client
+	// resubscribing with a Last-Event-ID. ok is false if streamID is
+	// unknown. A request for an afterID older than the stream's retained
+	// window still returns ok=true with whatever chunks remain buffered
+	// (chunks are best-effort beyond the retention limit, not guaranteed
+	// gap-free).
+	ReadFrom(streamID string, afterID uint64) (chunks []Chunk, result *json.RawMessage, failure *Failure, done bool, ok bool)
+
+	// Close releases any resources held by the manager.
+	Close() error
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auth provides ContextProviders and HTTP middleware for protecting
+// genkit.Handler/HandlerFunc flow endpoints, so users don't have to write
+// JWT verification plumbing themselves.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+
+	"github.com/firebase/genkit/go/core"
+)
+
+// OIDCOption configures an OIDC token verifier.
+type OIDCOption func(*oidcConfig)
+
+type oidcConfig struct {
+	httpClient *http.Client
+}
+
+// WithHTTPClient overrides the http.Client used for OIDC discovery and JWKS
+// fetching. Defaults to http.DefaultClient.
+func WithHTTPClient(c *http.Client) OIDCOption {
+	return func(cfg *oidcConfig) {
+		cfg.httpClient = c
+	}
+}
+
+// oidcVerifier lazily performs OIDC discovery and verifies bearer tokens
+// against the issuer's JWKS, refreshing keys on rotation via the
+// coreos/go-oidc verifier's own caching.
+type oidcVerifier struct {
+	issuerURL string
+	clientID  string
+	cfg       oidcConfig
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+func newOIDCVerifier(issuerURL, clientID string, opts ...OIDCOption) *oidcVerifier {
+	cfg := oidcConfig{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &oidcVerifier{issuerURL: issuerURL, clientID: clientID, cfg: cfg}
+}
+
+// get returns the cached verifier, performing OIDC discovery on the first
+// call. A discovery failure (for example, the issuer being briefly
+// unreachable at startup) is not cached, so the next call retries instead of
+// failing every request for the life of the process.
+func (v *oidcVerifier) get(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.verifier != nil {
+		return v.verifier, nil
+	}
+
+	ctx = oidc.ClientContext(ctx, v.cfg.httpClient)
+	provider, err := oidc.NewProvider(ctx, v.issuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("auth: discovering OIDC issuer %q: %w", v.issuerURL, err)
+	}
+	v.verifier = provider.Verifier(&oidc.Config{ClientID: v.clientID})
+	return v.verifier, nil
+}
+
+// verify validates an "Authorization: Bearer <jwt>" header value and returns
+// its claims as a raw map, alongside the verified subject and email if
+// present.
+func (v *oidcVerifier) verify(ctx context.Context, authHeader string) (claims map[string]any, sub, email string, err error) {
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == authHeader || token == "" {
+		return nil, "", "", fmt.Errorf("auth: missing or malformed Authorization header")
+	}
+
+	verifier, err := v.get(ctx)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	idToken, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("auth: invalid ID token: %w", err)
+	}
+
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, "", "", fmt.Errorf("auth: decoding claims: %w", err)
+	}
+	if e, ok := claims["email"].(string); ok {
+		email = e
+	}
+	return claims, idToken.Subject, email, nil
+}
+
+// OIDCProvider returns a core.ContextProvider that validates incoming
+// "Authorization: Bearer <jwt>" headers against issuerURL's JWKS (fetched
+// and cached per the underlying verifier, which re-fetches on key
+// rotation), and populates core.ActionContext with the verified "sub",
+// "email", "groups" and raw "claims" map. Use it with WithContextProviders
+// to protect a flow endpoint without writing JWT plumbing by hand:
+//
+//	genkit.HandlerFunc(flow, genkit.WithContextProviders(auth.OIDCProvider(issuer, clientID)))
+func OIDCProvider(issuerURL, clientID string, opts ...OIDCOption) core.ContextProvider {
+	v := newOIDCVerifier(issuerURL, clientID, opts...)
+	return func(ctx context.Context, req core.RequestData) (core.ActionContext, error) {
+		claims, sub, email, err := v.verify(ctx, authHeader(req))
+		if err != nil {
+			return nil, core.NewError(core.UNAUTHENTICATED, err.Error())
+		}
+
+		actionCtx := core.ActionContext{
+			"sub":    sub,
+			"claims": claims,
+		}
+		if email != "" {
+			actionCtx["email"] = email
+		}
+		if groups, ok := claims["groups"]; ok {
+			actionCtx["groups"] = groups
+		}
+		return actionCtx, nil
+	}
+}
+
+// authHeader extracts the Authorization header from a core.RequestData's
+// HTTP-shaped headers.
+func authHeader(req core.RequestData) string {
+	if req.Headers == nil {
+		return ""
+	}
+	values := req.Headers["Authorization"]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/core"
+)
+
+func TestOIDCProvider_MissingAuthorizationHeader(t *testing.T) {
+	// The issuer URL is unreachable and never dialed: verify rejects a
+	// missing/malformed header before it calls v.get, so this doesn't
+	// depend on network access (or its absence) to pass.
+	provider := OIDCProvider("https://issuer.example.com", "test-client-id")
+
+	_, err := provider(context.Background(), core.RequestData{})
+	if err == nil {
+		t.Fatal("want error for request with no Authorization header, got nil")
+	}
+
+	var gerr *core.GenkitError
+	if !errors.As(err, &gerr) || gerr.Status != core.UNAUTHENTICATED {
+		t.Errorf("want UNAUTHENTICATED GenkitError, got %v", err)
+	}
+}
+
+func TestOIDCVerifier_MalformedHeaderSkipsDiscovery(t *testing.T) {
+	var dialed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dialed = true
+		http.Error(w, "discovery should never be reached", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	v := newOIDCVerifier(server.URL, "test-client-id", WithHTTPClient(server.Client()))
+
+	_, _, _, err := v.verify(context.Background(), "not-a-bearer-token")
+	if err == nil {
+		t.Fatal("want error for a malformed Authorization header, got nil")
+	}
+	if dialed {
+		t.Error("want a malformed header to be rejected before any OIDC discovery call")
+	}
+}
+
+func TestRequireClaims_RejectsMissingToken(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	handler := RequireClaims("https://issuer.example.com", "test-client-id", func(claims map[string]any) bool {
+		return true
+	})(next)
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"test"}`))
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if called {
+		t.Error("want wrapped handler not to run when the token is missing")
+	}
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("want status code %d, got %d", http.StatusForbidden, resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "PERMISSION_DENIED") {
+		t.Errorf("want PERMISSION_DENIED in response body, got %q", string(body))
+	}
+}
+
+func TestOIDCVerifier_RetriesDiscoveryAfterFailure(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	var issuer string
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"issuer": %q,
+			"authorization_endpoint": %q,
+			"token_endpoint": %q,
+			"jwks_uri": %q
+		}`, issuer, issuer+"/authorize", issuer+"/token", issuer+"/jwks")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	issuer = server.URL
+
+	v := newOIDCVerifier(issuer, "test-client-id", WithHTTPClient(server.Client()))
+
+	if _, err := v.get(context.Background()); err == nil {
+		t.Fatal("want the first discovery attempt (which 503s) to fail")
+	}
+
+	verifier, err := v.get(context.Background())
+	if err != nil {
+		t.Fatalf("want the second discovery attempt to succeed, got %v", err)
+	}
+	if verifier == nil {
+		t.Fatal("want a non-nil verifier after a successful discovery")
+	}
+	if requests != 2 {
+		t.Errorf("want discovery to be retried exactly once after the failure, got %d requests", requests)
+	}
+}
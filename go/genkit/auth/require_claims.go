@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// RequireClaims returns middleware that validates the request's bearer
+// token against issuerURL the same way OIDCProvider does, then rejects it
+// with PERMISSION_DENIED, before the wrapped flow runs, unless predicate
+// accepts the token's claims. Wrap the http.HandlerFunc returned by
+// genkit.Handler with it directly:
+//
+//	mux.HandleFunc("/summarize", auth.RequireClaims(issuer, clientID, hasEditorRole)(genkit.Handler(summarizeFlow)))
+func RequireClaims(issuerURL, clientID string, predicate func(claims map[string]any) bool, opts ...OIDCOption) func(http.HandlerFunc) http.HandlerFunc {
+	v := newOIDCVerifier(issuerURL, clientID, opts...)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, _, _, err := v.verify(r.Context(), r.Header.Get("Authorization"))
+			if err != nil {
+				writePermissionDenied(w, err.Error())
+				return
+			}
+			if !predicate(claims) {
+				writePermissionDenied(w, "claims predicate rejected the request")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// writePermissionDenied writes a 403 response with an
+// {"error":{"status":"PERMISSION_DENIED","message":...}} envelope, the
+// status code and envelope shape genkit's Handler uses for a
+// core.GenkitError with status PERMISSION_DENIED. Unlike Handler's, the
+// envelope has no "details" field and message is not prefixed with the
+// status.
+func writePermissionDenied(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]string{
+			"status":  "PERMISSION_DENIED",
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,447 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package genkit is the entry point for defining and serving Genkit flows:
+// Init sets up a Genkit instance, DefineFlow/DefineStreamingFlow wrap typed
+// functions as actions, and Handler/HandlerFunc/GRPCServer expose them over
+// HTTP and gRPC.
+package genkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/firebase/genkit/go/core"
+)
+
+// defaultPromptDir is the directory under a configured prompt FS that
+// WithPromptFS/LookupPrompt search for *.prompt files when WithPromptDir
+// isn't given.
+const defaultPromptDir = "prompts"
+
+// Genkit holds the flows, prompts, and schemas registered in a process, plus
+// the configuration Init was given.
+type Genkit struct {
+	ctx context.Context
+	reg *registry
+
+	promptFS           fs.FS
+	promptDir          string
+	promptWatchEnabled bool
+	onPromptReload     func(name string)
+}
+
+// GenkitOption configures a Genkit instance at Init time.
+type GenkitOption func(*Genkit)
+
+// Init creates a Genkit instance, applying opts, loading any configured
+// prompt directory, and starting prompt hot-reload if WithPromptWatch was
+// enabled.
+func Init(ctx context.Context, opts ...GenkitOption) *Genkit {
+	g := &Genkit{
+		ctx:       ctx,
+		reg:       newRegistry(),
+		promptDir: defaultPromptDir,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.promptFS != nil {
+		if err := g.loadPrompts(); err != nil {
+			slog.Error("genkit: loading prompts", "promptDir", g.promptDir, "error", err)
+		}
+	}
+
+	if err := g.startPromptWatch(); err != nil {
+		slog.Error("genkit: starting prompt watcher", "promptDir", g.promptDir, "error", err)
+	}
+
+	return g
+}
+
+// WithPromptFS configures the filesystem Init loads *.prompt files from.
+// Relative paths are resolved under WithPromptDir (defaultPromptDir unless
+// set).
+func WithPromptFS(fsys fs.FS) GenkitOption {
+	return func(g *Genkit) {
+		g.promptFS = fsys
+	}
+}
+
+// WithPromptDir overrides the directory, relative to the FS given to
+// WithPromptFS, that prompts are loaded from.
+func WithPromptDir(dir string) GenkitOption {
+	return func(g *Genkit) {
+		g.promptDir = dir
+	}
+}
+
+// loadPrompts parses every *.prompt file under g.promptDir in g.promptFS and
+// registers it by name.
+func (g *Genkit) loadPrompts() error {
+	entries, err := fs.ReadDir(g.promptFS, g.promptDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".prompt") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".prompt")
+		data, err := fs.ReadFile(g.promptFS, path.Join(g.promptDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("genkit: reading prompt %q: %w", name, err)
+		}
+		prompt, err := parsePromptFile(name, data)
+		if err != nil {
+			return fmt.Errorf("genkit: parsing prompt %q: %w", name, err)
+		}
+		g.reg.registerPrompt(name, prompt)
+	}
+	return nil
+}
+
+// Prompt is a parsed .prompt file: YAML frontmatter describing the model and
+// input schema, followed by a Handlebars-style template body.
+type Prompt struct {
+	Name        string
+	Model       string
+	InputSchema map[string]string
+	Template    string
+}
+
+type promptFrontmatter struct {
+	Model string `yaml:"model"`
+	Input struct {
+		Schema map[string]string `yaml:"schema"`
+	} `yaml:"input"`
+}
+
+// parsePromptFile parses the contents of a .prompt file: a `---`-delimited
+// YAML frontmatter block followed by the prompt template.
+func parsePromptFile(name string, data []byte) (*Prompt, error) {
+	const delim = "---"
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	if !strings.HasPrefix(strings.TrimLeft(text, "\r\n"), delim) {
+		return &Prompt{Name: name, Template: text}, nil
+	}
+
+	text = strings.TrimLeft(text, "\r\n")
+	rest := strings.TrimPrefix(text, delim)
+	end := strings.Index(rest, "\n"+delim)
+	if end == -1 {
+		return nil, fmt.Errorf("prompt %q: unterminated frontmatter", name)
+	}
+
+	var fm promptFrontmatter
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return nil, fmt.Errorf("prompt %q: parsing frontmatter: %w", name, err)
+	}
+
+	template := strings.TrimPrefix(rest[end+len("\n"+delim):], "\n")
+	return &Prompt{
+		Name:        name,
+		Model:       fm.Model,
+		InputSchema: fm.Input.Schema,
+		Template:    template,
+	}, nil
+}
+
+// LookupPrompt returns the prompt registered under name, or nil if none was
+// loaded.
+func LookupPrompt(g *Genkit, name string) *Prompt {
+	return g.reg.lookupPrompt(name)
+}
+
+// registry holds the schemas and prompts DefineSchemaFor and the prompt
+// loader register against a Genkit instance; flows are not kept here. It's
+// guarded by mu since prompts are reloaded
+// from the fsnotify watcher goroutine (see prompt_watch.go) concurrently
+// with lookups from request-handling goroutines.
+type registry struct {
+	mu      sync.RWMutex
+	schemas map[string]map[string]any
+	prompts map[string]*Prompt
+}
+
+func newRegistry() *registry {
+	return &registry{
+		schemas: map[string]map[string]any{},
+		prompts: map[string]*Prompt{},
+	}
+}
+
+func (r *registry) registerSchema(name string, schema map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[name] = schema
+}
+
+// LookupSchema returns the JSON schema registered under name via
+// DefineSchemaFor, or nil if none was registered.
+func (r *registry) LookupSchema(name string) map[string]any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.schemas[name]
+}
+
+func (r *registry) registerPrompt(name string, p *Prompt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts[name] = p
+}
+
+func (r *registry) lookupPrompt(name string) *Prompt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.prompts[name]
+}
+
+// DefineSchemaFor builds a JSON schema for T by reflection and registers it
+// under T's type name, so it can later be retrieved by tools (the dev UI,
+// prompt input validation) via the registry. It panics if T's shape can't be
+// represented as a JSON schema (for example, a field whose type can't be
+// JSON-marshaled).
+func DefineSchemaFor[T any](g *Genkit) map[string]any {
+	var zero T
+	t := reflect.TypeOf(zero)
+	name := t.Name()
+
+	schema, err := schemaForType(t)
+	if err != nil {
+		panic(fmt.Sprintf("genkit: DefineSchemaFor[%s]: %v", name, err))
+	}
+
+	g.reg.registerSchema(name, schema)
+	return schema
+}
+
+// schemaForType builds a minimal JSON schema object for a struct type:
+// "type": "object", one property per exported field (named by its json tag,
+// if any), and "required" listing every field without `omitempty`.
+func schemaForType(t reflect.Type) (map[string]any, error) {
+	if t.Kind() != reflect.Struct {
+		jsType, err := jsonSchemaType(t)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": jsType}, nil
+	}
+
+	properties := map[string]any{}
+	var required []any
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldName, omitempty := jsonFieldName(field)
+		if fieldName == "-" {
+			continue
+		}
+
+		jsType, err := jsonSchemaType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		properties[fieldName] = map[string]any{"type": jsType}
+
+		if !omitempty {
+			required = append(required, fieldName)
+		}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}, nil
+}
+
+// jsonFieldName returns the JSON property name for field (honoring a `json`
+// struct tag) and whether it's marked omitempty.
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// jsonSchemaType maps a Go kind to the JSON schema "type" keyword value it
+// corresponds to. Kinds with no JSON representation (func, chan, complex,
+// unsafe pointer) return an error, which DefineSchemaFor turns into a panic.
+func jsonSchemaType(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", nil
+	case reflect.Float32, reflect.Float64:
+		return "number", nil
+	case reflect.Slice, reflect.Array:
+		return "array", nil
+	case reflect.Map, reflect.Struct:
+		return "object", nil
+	case reflect.Ptr:
+		return jsonSchemaType(t.Elem())
+	default:
+		return "", fmt.Errorf("type %s has no JSON schema representation", t)
+	}
+}
+
+// Flow wraps a typed function as a core.Action, marshaling its JSON input
+// and output so it can be run generically by Handler/HandlerFunc/GRPCServer.
+type Flow[I, O any] struct {
+	name string
+	fn   func(ctx context.Context, input I) (O, error)
+}
+
+// DefineFlow returns a *Flow[I, O] named name that wraps fn and can be run
+// directly or exposed via Handler/HandlerFunc/GRPCServer. The flow is not
+// added to g's registry.
+func DefineFlow[I, O any](g *Genkit, name string, fn func(ctx context.Context, input I) (O, error)) *Flow[I, O] {
+	return &Flow[I, O]{name: name, fn: fn}
+}
+
+func (f *Flow[I, O]) Name() string { return f.name }
+
+// Run invokes the flow directly with a typed input.
+func (f *Flow[I, O]) Run(ctx context.Context, input I) (O, error) {
+	return f.fn(ctx, input)
+}
+
+func (f *Flow[I, O]) RunJSON(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error) {
+	var in I
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &in); err != nil {
+			return nil, err
+		}
+	}
+	out, err := f.fn(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// streamingCapable is implemented by *StreamingFlow so HandlerFunc/GRPCServer
+// can tell it apart from a plain *Flow without needing a type switch over
+// every instantiation.
+type streamingCapable interface {
+	streamingFlow() bool
+}
+
+func (f *Flow[I, O]) streamingFlow() bool { return false }
+
+// StreamingFlow wraps a typed streaming function as a core.Action, the same
+// way Flow does for a non-streaming one.
+type StreamingFlow[I, O any] struct {
+	name string
+	fn   func(ctx context.Context, input I, cb func(context.Context, O) error) (O, error)
+}
+
+// DefineStreamingFlow returns a *StreamingFlow[I, O] named name that wraps
+// fn; like DefineFlow, it is not added to g's registry. fn's cb is called with each intermediate chunk; its
+// return value is the flow's final output.
+func DefineStreamingFlow[I, O any](g *Genkit, name string, fn func(ctx context.Context, input I, cb func(context.Context, O) error) (O, error)) *StreamingFlow[I, O] {
+	return &StreamingFlow[I, O]{name: name, fn: fn}
+}
+
+func (f *StreamingFlow[I, O]) Name() string { return f.name }
+
+func (f *StreamingFlow[I, O]) streamingFlow() bool { return true }
+
+func (f *StreamingFlow[I, O]) RunJSON(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error) {
+	var in I
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &in); err != nil {
+			return nil, err
+		}
+	}
+
+	var innerCb func(context.Context, O) error
+	if cb != nil {
+		innerCb = func(ctx context.Context, chunk O) error {
+			data, err := json.Marshal(chunk)
+			if err != nil {
+				return err
+			}
+			return cb(ctx, data)
+		}
+	}
+
+	out, err := f.fn(ctx, in, innerCb)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// errStreamStopped is returned by Stream's internal callback to unwind fn
+// early once the caller's yield function returns false.
+var errStreamStopped = fmt.Errorf("genkit: stream consumer stopped iteration")
+
+// Stream runs the flow and returns a range-over-func iterator yielding one
+// *core.StreamingFlowValue per intermediate chunk, followed by a final value
+// with Done set and Output populated.
+func (f *StreamingFlow[I, O]) Stream(ctx context.Context, input I) func(yield func(*core.StreamingFlowValue[O, O], error) bool) {
+	return func(yield func(*core.StreamingFlowValue[O, O], error) bool) {
+		cb := func(ctx context.Context, chunk O) error {
+			if !yield(&core.StreamingFlowValue[O, O]{Stream: chunk}, nil) {
+				return errStreamStopped
+			}
+			return nil
+		}
+
+		out, err := f.fn(ctx, input, cb)
+		if err != nil {
+			if err != errStreamStopped {
+				yield(nil, err)
+			}
+			return
+		}
+		yield(&core.StreamingFlowValue[O, O]{Output: out, Done: true}, nil)
+	}
+}
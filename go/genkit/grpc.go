@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package genkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	gmetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit/grpcpb"
+)
+
+// grpcServerConfig holds the options collected from a GRPCServer call's
+// GRPCServerOption arguments.
+type grpcServerConfig struct {
+	contextProviders []core.ContextProvider
+}
+
+// GRPCServerOption configures a GRPCServer call.
+type GRPCServerOption func(*grpcServerConfig)
+
+// WithGRPCContextProviders runs each provider against the inbound call's
+// metadata (mirroring WithContextProviders' treatment of HTTP headers) and
+// merges the resulting core.ActionContext values (later providers win on
+// key conflicts) into the context the flow runs with. Without this option,
+// gRPC calls run with no action context, the same as an HTTP request
+// handled with no WithContextProviders.
+func WithGRPCContextProviders(providers ...core.ContextProvider) GRPCServerOption {
+	return func(c *grpcServerConfig) {
+		c.contextProviders = append(c.contextProviders, providers...)
+	}
+}
+
+// GRPCServer returns a *grpc.Server that exposes the given flows over gRPC,
+// as a sibling to the HTTP Handler/HandlerFunc surface. It registers a
+// single genkit.v1.FlowService with a generic Invoke unary RPC and a Stream
+// server-streaming RPC, so callers identify the flow to run by name rather
+// than requiring one RPC method per flow.
+//
+// Incoming gRPC metadata is passed to any configured
+// WithGRPCContextProviders the same way HTTP headers are passed via
+// WithContextProviders. A Stream response carries the same three outcomes
+// as the SSE handler's {"message":...} / {"result":...} / {"error":...}
+// envelopes, as grpcpb.StreamChunk messages: a chunk's JSON in
+// message_json, the final output's JSON in result_json, and a failure's
+// status/message/details in error.
+//
+// The service is served over grpcpb.Codec, a JSON codec, not the standard
+// protobuf wire format: genkit.pb.go's types are hand-maintained reference
+// documentation for genkit.proto, not protoc-gen-go output. A genkit-go
+// client built with grpcpb.NewFlowServiceClient interoperates with this
+// server out of the box; a client generated by protoc in another language
+// does not, since it will speak real protobuf wire format against a server
+// that only understands JSON. Fronting this server with a true
+// protoc-gen-go/grpc-gateway client requires regenerating genkit.proto with
+// protoc and serving the result over the standard codec instead.
+func GRPCServer(g *Genkit, flows []core.Action, opts ...GRPCServerOption) *grpc.Server {
+	cfg := &grpcServerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	srv := grpc.NewServer(grpc.ForceServerCodec(grpcpb.Codec{}))
+	grpcpb.RegisterFlowServiceServer(srv, newFlowServiceServer(g, flows, cfg))
+	return srv
+}
+
+// flowServiceServer implements grpcpb.FlowServiceServer on top of the
+// registered core.Action flows.
+type flowServiceServer struct {
+	grpcpb.UnimplementedFlowServiceServer
+
+	g     *Genkit
+	flows map[string]core.Action
+	cfg   *grpcServerConfig
+}
+
+func newFlowServiceServer(g *Genkit, flows []core.Action, cfg *grpcServerConfig) *flowServiceServer {
+	byName := make(map[string]core.Action, len(flows))
+	for _, f := range flows {
+		byName[f.Name()] = f
+	}
+	return &flowServiceServer{g: g, flows: byName, cfg: cfg}
+}
+
+func (s *flowServiceServer) lookup(name string) (core.Action, error) {
+	f, ok := s.flows[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "flow %q is not registered with this server", name)
+	}
+	return f, nil
+}
+
+// actionContext runs s.cfg's configured WithGRPCContextProviders against the
+// call's incoming metadata and input, mirroring how withRequestActionContext
+// does the same for HTTP headers via WithContextProviders.
+func (s *flowServiceServer) actionContext(ctx context.Context, rawInput json.RawMessage) (core.ActionContext, error) {
+	if len(s.cfg.contextProviders) == 0 {
+		return nil, nil
+	}
+
+	req := core.RequestData{
+		Headers: metadataToHeaders(ctx),
+		Input:   rawInput,
+	}
+
+	merged := core.ActionContext{}
+	for _, provider := range s.cfg.contextProviders {
+		ac, err := provider(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range ac {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
+
+// metadataToHeaders flattens incoming gRPC metadata into the
+// http.Header-shaped map core.RequestData expects. Each key is stored both
+// as received (gRPC metadata keys are lowercase) and in canonical HTTP form,
+// so ContextProviders written against HTTP headers work unmodified over gRPC.
+func metadataToHeaders(ctx context.Context) map[string][]string {
+	md, ok := gmetadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	headers := make(map[string][]string, len(md))
+	for k, v := range md {
+		headers[k] = v
+		headers[http.CanonicalHeaderKey(k)] = v
+	}
+	return headers
+}
+
+func (s *flowServiceServer) Invoke(ctx context.Context, req *grpcpb.InvokeRequest) (*grpcpb.InvokeResponse, error) {
+	action, err := s.lookup(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	input := json.RawMessage(req.InputJson)
+	actionCtx, err := s.actionContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if actionCtx != nil {
+		ctx = core.WithActionContext(ctx, actionCtx)
+	}
+
+	output, err := action.RunJSON(ctx, input, nil)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &grpcpb.InvokeResponse{OutputJson: string(output)}, nil
+}
+
+func (s *flowServiceServer) Stream(req *grpcpb.InvokeRequest, stream grpcpb.FlowService_StreamServer) error {
+	ctx := stream.Context()
+
+	action, err := s.lookup(req.Name)
+	if err != nil {
+		return err
+	}
+
+	input := json.RawMessage(req.InputJson)
+	actionCtx, err := s.actionContext(ctx, input)
+	if err != nil {
+		return err
+	}
+	if actionCtx != nil {
+		ctx = core.WithActionContext(ctx, actionCtx)
+	}
+
+	cb := func(ctx context.Context, chunk json.RawMessage) error {
+		return stream.Send(&grpcpb.StreamChunk{MessageJson: string(chunk)})
+	}
+
+	output, err := action.RunJSON(ctx, input, cb)
+	if err != nil {
+		var gerr *core.GenkitError
+		if errors.As(err, &gerr) {
+			return stream.Send(&grpcpb.StreamChunk{Error: &grpcpb.StreamError{
+				Status:  string(gerr.Status),
+				Message: "stream flow error",
+				Details: gerr.Message,
+			}})
+		}
+		return stream.Send(&grpcpb.StreamChunk{Error: &grpcpb.StreamError{
+			Status:  "INTERNAL",
+			Message: "stream flow error",
+			Details: err.Error(),
+		}})
+	}
+
+	return stream.Send(&grpcpb.StreamChunk{ResultJson: string(output)})
+}
+
+// grpcError maps a core.GenkitError to the equivalent gRPC status, falling
+// back to codes.Internal for plain errors the way the HTTP Handler falls
+// back to a 500.
+func grpcError(err error) error {
+	var gerr *core.GenkitError
+	if !errors.As(err, &gerr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return status.Error(genkitCodeToGRPC(gerr.Status), fmt.Sprintf("%s: %s", gerr.Status, gerr.Message))
+}
+
+// genkitCodeToGRPC maps core.StatusName values to the equivalent
+// google.golang.org/grpc/codes.Code.
+func genkitCodeToGRPC(status core.StatusName) codes.Code {
+	switch status {
+	case core.INVALID_ARGUMENT:
+		return codes.InvalidArgument
+	case core.NOT_FOUND:
+		return codes.NotFound
+	case core.PERMISSION_DENIED:
+		return codes.PermissionDenied
+	case core.UNAUTHENTICATED:
+		return codes.Unauthenticated
+	case core.ALREADY_EXISTS:
+		return codes.AlreadyExists
+	case core.FAILED_PRECONDITION:
+		return codes.FailedPrecondition
+	case core.ABORTED:
+		return codes.Aborted
+	case core.OUT_OF_RANGE:
+		return codes.OutOfRange
+	case core.UNIMPLEMENTED:
+		return codes.Unimplemented
+	case core.UNAVAILABLE:
+		return codes.Unavailable
+	case core.DEADLINE_EXCEEDED:
+		return codes.DeadlineExceeded
+	case core.CANCELLED:
+		return codes.Canceled
+	case core.RESOURCE_EXHAUSTED:
+		return codes.ResourceExhausted
+	case core.INTERNAL:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package genkit
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit/grpcpb"
+)
+
+// fakeAction is a minimal core.Action used to exercise GRPCServer without a
+// real flow registry.
+type fakeAction struct {
+	name string
+	run  func(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error)
+}
+
+func (f *fakeAction) Name() string { return f.name }
+
+func (f *fakeAction) RunJSON(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error) {
+	return f.run(ctx, input, cb)
+}
+
+func dialGRPCServer(t *testing.T, srv *grpc.Server) grpcpb.FlowServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(grpcpb.Codec{})),
+	)
+	if err != nil {
+		t.Fatalf("dialing bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return grpcpb.NewFlowServiceClient(conn)
+}
+
+func TestGRPCServer_Invoke(t *testing.T) {
+	g := Init(context.Background())
+
+	echo := &fakeAction{
+		name: "echo",
+		run: func(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error) {
+			return input, nil
+		},
+	}
+
+	client := dialGRPCServer(t, GRPCServer(g, []core.Action{echo}))
+
+	resp, err := client.Invoke(context.Background(), &grpcpb.InvokeRequest{
+		Name:      "echo",
+		InputJson: `"hello"`,
+	})
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if resp.OutputJson != `"hello"` {
+		t.Errorf("want output %q, got %q", `"hello"`, resp.OutputJson)
+	}
+}
+
+func TestGRPCServer_Invoke_UnknownFlow(t *testing.T) {
+	g := Init(context.Background())
+	client := dialGRPCServer(t, GRPCServer(g, nil))
+
+	_, err := client.Invoke(context.Background(), &grpcpb.InvokeRequest{Name: "missing", InputJson: `{}`})
+	if err == nil {
+		t.Fatal("want error for unregistered flow, got nil")
+	}
+}
+
+func TestGRPCServer_Stream(t *testing.T) {
+	g := Init(context.Background())
+
+	counting := &fakeAction{
+		name: "counting",
+		run: func(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error) {
+			for _, c := range []string{`"a"`, `"b"`} {
+				if err := cb(ctx, json.RawMessage(c)); err != nil {
+					return nil, err
+				}
+			}
+			return json.RawMessage(`"done"`), nil
+		},
+	}
+
+	client := dialGRPCServer(t, GRPCServer(g, []core.Action{counting}))
+
+	stream, err := client.Stream(context.Background(), &grpcpb.InvokeRequest{Name: "counting", InputJson: `{}`})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	var messages []string
+	var result string
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		if chunk.MessageJson != "" {
+			messages = append(messages, chunk.MessageJson)
+		}
+		if chunk.ResultJson != "" {
+			result = chunk.ResultJson
+		}
+	}
+
+	if len(messages) != 2 || messages[0] != `"a"` || messages[1] != `"b"` {
+		t.Errorf("want messages [\"a\" \"b\"], got %v", messages)
+	}
+	if result != `"done"` {
+		t.Errorf("want result %q, got %q", `"done"`, result)
+	}
+}
+
+func TestGRPCServer_WithGRPCContextProviders(t *testing.T) {
+	g := Init(context.Background())
+
+	var gotSub string
+	whoami := &fakeAction{
+		name: "whoami",
+		run: func(ctx context.Context, input json.RawMessage, cb func(context.Context, json.RawMessage) error) (json.RawMessage, error) {
+			if ac := core.FromContext(ctx); ac != nil {
+				gotSub, _ = ac["sub"].(string)
+			}
+			return json.RawMessage(`"ok"`), nil
+		},
+	}
+
+	provider := func(ctx context.Context, req core.RequestData) (core.ActionContext, error) {
+		values := req.Headers["authorization"]
+		if len(values) == 0 {
+			return nil, nil
+		}
+		return core.ActionContext{"sub": values[0]}, nil
+	}
+
+	client := dialGRPCServer(t, GRPCServer(g, []core.Action{whoami}, WithGRPCContextProviders(provider)))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "user-123")
+	_, err := client.Invoke(ctx, &grpcpb.InvokeRequest{Name: "whoami", InputJson: `{}`})
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+
+	if gotSub != "user-123" {
+		t.Errorf("want provider's ActionContext to reach the flow with sub %q, got %q", "user-123", gotSub)
+	}
+}
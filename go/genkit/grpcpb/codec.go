@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcpb
+
+import "encoding/json"
+
+// Codec is a grpc/encoding.Codec that marshals FlowService messages as
+// plain JSON instead of the protobuf wire format. Flow inputs, outputs and
+// chunks in InvokeRequest/InvokeResponse/StreamChunk are already
+// JSON-encoded strings, so there's no schema-per-flow to generate proto
+// descriptors for;
+// a JSON codec lets these structs stay ordinary Go types instead of
+// requiring a protoc-gen-go toolchain run.
+//
+// Servers built with GRPCServer and clients built with NewFlowServiceClient
+// both force this codec; it only needs to be named here, not wired up by
+// callers.
+type Codec struct{}
+
+// Name implements grpc/encoding.Codec. It deliberately does not shadow the
+// built-in "proto" codec name, since this package doesn't implement
+// proto.Message and isn't meant to interoperate with protobuf-wire clients.
+func (Codec) Name() string { return "genkit-json" }
+
+func (Codec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (Codec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
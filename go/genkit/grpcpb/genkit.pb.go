@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package grpcpb defines the wire messages for genkit.v1.FlowService,
+// described in genkit.proto. These types are hand-maintained, not
+// protoc-generated: since flow inputs, outputs and chunks already travel as
+// JSON-encoded strings, the service is served over Codec, a JSON codec (see
+// codec.go), rather than the
+// standard protobuf wire format, so these structs only need to round-trip
+// through encoding/json, not implement proto.Message.
+package grpcpb
+
+type InvokeRequest struct {
+	// Name is the registered flow name, as passed to genkit.DefineFlow.
+	Name string `json:"name"`
+	// InputJson is the flow input, JSON-encoded.
+	InputJson string `json:"input_json"`
+}
+
+type InvokeResponse struct {
+	// OutputJson is the flow output, JSON-encoded.
+	OutputJson string `json:"output_json"`
+}
+
+// StreamChunk is one chunk of a Stream response. Exactly one of
+// MessageJson, ResultJson, Error is set, mirroring the proto `oneof` in
+// genkit.proto.
+type StreamChunk struct {
+	// MessageJson is an intermediate streaming chunk, JSON-encoded.
+	MessageJson string `json:"message_json,omitempty"`
+	// ResultJson is the final flow output, JSON-encoded. Terminates the stream.
+	ResultJson string `json:"result_json,omitempty"`
+	// Error terminates the stream with a failure.
+	Error *StreamError `json:"error,omitempty"`
+}
+
+type StreamError struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Hand-maintained client/server plumbing for genkit.v1.FlowService,
+// described in genkit.proto. There is no protoc-gen-go-grpc toolchain run
+// here: the service uses Codec (see codec.go) instead of the standard
+// protobuf codec, so the usual generated stubs, which assume proto.Message
+// request/response types, don't apply. Keep this in sync with
+// genkit.proto/genkit.pb.go by hand if the service surface changes.
+package grpcpb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+const (
+	FlowService_Invoke_FullMethodName = "/genkit.v1.FlowService/Invoke"
+	FlowService_Stream_FullMethodName = "/genkit.v1.FlowService/Stream"
+)
+
+// FlowServiceClient is the client API for FlowService service.
+type FlowServiceClient interface {
+	// Invoke runs a flow to completion and returns its JSON-encoded output.
+	Invoke(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (*InvokeResponse, error)
+	// Stream runs a streaming flow, emitting one StreamChunk per callback
+	// invocation followed by a final chunk carrying the result or an error.
+	Stream(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (FlowService_StreamClient, error)
+}
+
+type flowServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewFlowServiceClient returns a FlowServiceClient that dials over cc. Calls
+// are always made with Codec forced, regardless of what dial/call options cc
+// was created with.
+func NewFlowServiceClient(cc grpc.ClientConnInterface) FlowServiceClient {
+	return &flowServiceClient{cc}
+}
+
+func withCodec(opts []grpc.CallOption) []grpc.CallOption {
+	return append([]grpc.CallOption{grpc.ForceCodec(Codec{})}, opts...)
+}
+
+func (c *flowServiceClient) Invoke(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (*InvokeResponse, error) {
+	out := new(InvokeResponse)
+	err := c.cc.Invoke(ctx, FlowService_Invoke_FullMethodName, in, out, withCodec(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flowServiceClient) Stream(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (FlowService_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FlowService_ServiceDesc.Streams[0], FlowService_Stream_FullMethodName, withCodec(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flowServiceStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FlowService_StreamClient interface {
+	Recv() (*StreamChunk, error)
+	grpc.ClientStream
+}
+
+type flowServiceStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *flowServiceStreamClient) Recv() (*StreamChunk, error) {
+	m := new(StreamChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FlowServiceServer is the server API for FlowService service.
+// All implementations must embed UnimplementedFlowServiceServer for
+// forward compatibility.
+type FlowServiceServer interface {
+	// Invoke runs a flow to completion and returns its JSON-encoded output.
+	Invoke(context.Context, *InvokeRequest) (*InvokeResponse, error)
+	// Stream runs a streaming flow, emitting one StreamChunk per callback
+	// invocation followed by a final chunk carrying the result or an error.
+	Stream(*InvokeRequest, FlowService_StreamServer) error
+	mustEmbedUnimplementedFlowServiceServer()
+}
+
+// UnimplementedFlowServiceServer must be embedded to have forward
+// compatible implementations.
+type UnimplementedFlowServiceServer struct{}
+
+func (UnimplementedFlowServiceServer) Invoke(context.Context, *InvokeRequest) (*InvokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invoke not implemented")
+}
+
+func (UnimplementedFlowServiceServer) Stream(*InvokeRequest, FlowService_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+
+func (UnimplementedFlowServiceServer) mustEmbedUnimplementedFlowServiceServer() {}
+
+// UnsafeFlowServiceServer may be embedded to opt out of forward
+// compatibility for this service.
+type UnsafeFlowServiceServer interface {
+	mustEmbedUnimplementedFlowServiceServer()
+}
+
+// RegisterFlowServiceServer registers srv with s. Callers building s with
+// GRPCServer already get this and the required Codec wired up; a
+// hand-rolled *grpc.Server must also pass grpc.ForceServerCodec(Codec{}).
+func RegisterFlowServiceServer(s grpc.ServiceRegistrar, srv FlowServiceServer) {
+	s.RegisterService(&FlowService_ServiceDesc, srv)
+}
+
+func _FlowService_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlowServiceServer).Invoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlowService_Invoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlowServiceServer).Invoke(ctx, req.(*InvokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlowService_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvokeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlowServiceServer).Stream(m, &flowServiceStreamServer{stream})
+}
+
+type FlowService_StreamServer interface {
+	Send(*StreamChunk) error
+	grpc.ServerStream
+}
+
+type flowServiceStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *flowServiceStreamServer) Send(m *StreamChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// FlowService_ServiceDesc is the grpc.ServiceDesc for FlowService service.
+// It's only intended for direct use with grpc.RegisterService, and not to be
+// introspected or modified (even as a copy).
+var FlowService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "genkit.v1.FlowService",
+	HandlerType: (*FlowServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Invoke",
+			Handler:    _FlowService_Invoke_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _FlowService_Stream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "genkit.proto",
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package genkit
+
+import (
+	"bufio"
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// streamFormat is the wire format a streaming response is encoded in,
+// chosen per request via content negotiation on the Accept header.
+type streamFormat int
+
+const (
+	// streamFormatSSE is the default: "data: {...}\n\n" framing.
+	streamFormatSSE streamFormat = iota
+	// streamFormatNDJSON writes one JSON object per line, with no other
+	// framing, for clients (gRPC-gateway, CLIs) that don't want to parse
+	// SSE.
+	streamFormatNDJSON
+)
+
+// ndjsonMIMETypes are the Accept values that select newline-delimited JSON
+// instead of SSE. Both application/x-ndjson and application/jsonl are in
+// use in the wild for the same format; accept either.
+var ndjsonMIMETypes = map[string]bool{
+	"application/x-ndjson": true,
+	"application/jsonl":    true,
+}
+
+// negotiateStreamFormat inspects the request's Accept header and returns the
+// format a streaming Handler response should use. It falls back to SSE,
+// genkit's long-standing default, for anything else (including
+// text/event-stream and a missing/empty header).
+func negotiateStreamFormat(r *http.Request) streamFormat {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range splitAcceptHeader(accept) {
+			mediaType, _, err := mime.ParseMediaType(part)
+			if err != nil {
+				continue
+			}
+			if ndjsonMIMETypes[mediaType] {
+				return streamFormatNDJSON
+			}
+		}
+	}
+	return streamFormatSSE
+}
+
+func splitAcceptHeader(accept string) []string {
+	var parts []string
+	start := 0
+	for i, r := range accept {
+		if r == ',' {
+			parts = append(parts, accept[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, accept[start:])
+}
+
+// streamEnvelopeWriter writes the {"message":...}/{"result":...}/{"error":...}
+// envelopes a streaming Handler response emits, in either SSE or ndjson
+// framing. Both formats carry the same durable-resume chunk IDs: SSE as an
+// `id:` field per the spec, ndjson as an `"id"` property on the envelope
+// object, since ndjson has no framing of its own to carry it in.
+type streamEnvelopeWriter struct {
+	w      *bufio.Writer
+	format streamFormat
+	// flush is called after every envelope is flushed through w, to push
+	// bytes past net/http's own response buffering (http.Flusher.Flush) so a
+	// real client sees each chunk as it's produced instead of only once the
+	// handler returns or the buffer fills. It's nil if the underlying
+	// http.ResponseWriter doesn't implement http.Flusher.
+	flush func()
+}
+
+func newStreamEnvelopeWriter(w *bufio.Writer, format streamFormat, flush func()) *streamEnvelopeWriter {
+	return &streamEnvelopeWriter{w: w, format: format, flush: flush}
+}
+
+// writeEnvelope marshals envelope (one of the message/result/error payload
+// shapes) and writes it framed per s.format. id is the durable-resume chunk
+// ID, or 0 if the response isn't backed by a StreamManager.
+func (s *streamEnvelopeWriter) writeEnvelope(envelope any, id uint64) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	switch s.format {
+	case streamFormatNDJSON:
+		if id != 0 {
+			data, err = withEnvelopeID(data, id)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := s.w.Write(data); err != nil {
+			return err
+		}
+		if err := s.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	default:
+		if id != 0 {
+			if _, err := s.w.WriteString("id: "); err != nil {
+				return err
+			}
+			if _, err := s.w.WriteString(uintToString(id)); err != nil {
+				return err
+			}
+			if _, err := s.w.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := s.w.WriteString("data: "); err != nil {
+			return err
+		}
+		if _, err := s.w.Write(data); err != nil {
+			return err
+		}
+		if _, err := s.w.WriteString("\n\n"); err != nil {
+			return err
+		}
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if s.flush != nil {
+		s.flush()
+	}
+	return nil
+}
+
+// contentTypeFor returns the Content-Type header a streaming Handler
+// response should be served with for format.
+func contentTypeFor(format streamFormat) string {
+	if format == streamFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/event-stream"
+}
+
+// withEnvelopeID adds an "id" property to a JSON object's top level, used to
+// carry the durable-resume chunk ID in ndjson framing.
+func withEnvelopeID(data []byte, id uint64) ([]byte, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	idJSON, err := json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+	obj["id"] = idJSON
+	return json.Marshal(obj)
+}
+
+func uintToString(n uint64) string {
+	if n == 0 {
+		return "0"
+	}
+	var buf [20]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return string(buf[i:])
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package genkit
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// OSPath is implemented by an fs.FS that also knows the real directory it's
+// rooted at, so WithPromptWatch can tell whether WithPromptFS was given a
+// watchable on-disk directory (as opposed to an embed.FS or an in-memory
+// fstest.MapFS). DirFS returns an fs.FS that satisfies it.
+type OSPath interface {
+	fs.FS
+	// OSPath returns the absolute or relative OS path the FS is rooted at.
+	OSPath() string
+}
+
+// DirFS returns an fs.FS rooted at dir, like os.DirFS, that also implements
+// OSPath so it can be watched for changes via WithPromptWatch.
+func DirFS(dir string) OSPath {
+	return osDirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+type osDirFS struct {
+	fs.FS
+	dir string
+}
+
+func (o osDirFS) OSPath() string { return o.dir }
+
+// WithPromptWatch enables hot-reloading of prompts loaded via WithPromptFS.
+// It only takes effect when that FS also implements OSPath (e.g. one
+// returned by DirFS) — prompts loaded from an embed.FS or an in-memory FS
+// can't be watched, so the option is silently ignored for those. While
+// enabled, changed *.prompt files under the configured prompt directory are
+// re-parsed and atomically swapped into the registry so LookupPrompt
+// returns the new version without a process restart.
+func WithPromptWatch(enabled bool) GenkitOption {
+	return func(g *Genkit) {
+		g.promptWatchEnabled = enabled
+	}
+}
+
+// OnPromptReload registers a callback invoked after a watched prompt file is
+// successfully re-parsed and swapped into the registry, with the prompt's
+// registered name. It's meant for observability (metrics, logs) rather than
+// control flow — a non-nil error from a failed reload is logged and does
+// not call back, so the previous, still-valid prompt keeps serving.
+func OnPromptReload(fn func(name string)) GenkitOption {
+	return func(g *Genkit) {
+		g.onPromptReload = fn
+	}
+}
+
+// startPromptWatch is called once by Init, after prompts have been loaded
+// for the first time, when g.promptWatchEnabled is set and g.promptFS
+// implements OSPath. It runs until the context given to Init is done.
+func (g *Genkit) startPromptWatch() error {
+	if !g.promptWatchEnabled {
+		return nil
+	}
+	rooted, ok := g.promptFS.(OSPath)
+	if !ok {
+		slog.Warn("genkit: WithPromptWatch set but the prompt FS isn't backed by a real directory; ignoring",
+			"promptDir", g.promptDir)
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	root := filepath.Join(rooted.OSPath(), g.promptDir)
+	if err := watcher.Add(root); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go g.runPromptWatch(watcher, root)
+	return nil
+}
+
+func (g *Genkit) runPromptWatch(watcher *fsnotify.Watcher, root string) {
+	defer watcher.Close()
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !strings.HasSuffix(event.Name, ".prompt") {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			g.reloadPrompt(root, event.Name)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Error("genkit: prompt watcher error", "error", err)
+		}
+	}
+}
+
+// reloadPrompt re-parses the changed file at path (relative to root) and
+// atomically swaps the result into the registry under its prompt name,
+// logging the reload as a structured slog record and invoking OnPromptReload
+// on success. No trace span is recorded for a reload.
+func (g *Genkit) reloadPrompt(root, path string) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		slog.Error("genkit: resolving changed prompt path", "path", path, "error", err)
+		return
+	}
+	name := strings.TrimSuffix(filepath.Base(rel), ".prompt")
+
+	data, err := fs.ReadFile(g.promptFS, filepath.Join(g.promptDir, rel))
+	if err != nil {
+		slog.Error("genkit: reading changed prompt file", "name", name, "error", err)
+		return
+	}
+
+	prompt, err := parsePromptFile(name, data)
+	if err != nil {
+		slog.Error("genkit: reparsing changed prompt file", "name", name, "error", err)
+		return
+	}
+
+	g.reg.registerPrompt(name, prompt)
+	slog.Info("genkit: reloaded prompt", "name", name)
+
+	if g.onPromptReload != nil {
+		g.onPromptReload(name)
+	}
+}
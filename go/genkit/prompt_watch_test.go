@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package genkit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestDirFS_ImplementsOSPath(t *testing.T) {
+	dir := t.TempDir()
+
+	fsys := DirFS(dir)
+	if fsys.OSPath() != dir {
+		t.Errorf("want OSPath() %q, got %q", dir, fsys.OSPath())
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("temp dir unexpectedly missing: %v", err)
+	}
+}
+
+func TestWithPromptWatch_IgnoredForNonOSPathFS(t *testing.T) {
+	g := Init(context.Background(), WithPromptFS(fstest.MapFS{}), WithPromptWatch(true))
+
+	if _, ok := g.promptFS.(OSPath); ok {
+		t.Fatal("test setup invariant broken: fstest.MapFS must not implement OSPath")
+	}
+
+	// startPromptWatch must not error or block even though the configured
+	// FS can't be watched; it should just skip watching.
+	if err := g.startPromptWatch(); err != nil {
+		t.Errorf("want nil error for a non-watchable FS, got %v", err)
+	}
+}
+
+func TestWithPromptWatch_ReloadsChangedFile(t *testing.T) {
+	dir := t.TempDir()
+	promptPath := filepath.Join(dir, "greet.prompt")
+
+	write := func(text string) {
+		t.Helper()
+		if err := os.WriteFile(promptPath, []byte(text), 0o644); err != nil {
+			t.Fatalf("writing prompt file: %v", err)
+		}
+	}
+
+	write(`---
+model: googleai/gemini-2.5-flash
+input:
+  schema:
+    text: string
+---
+{{text}}`)
+
+	reloaded := make(chan string, 1)
+	g := Init(context.Background(),
+		WithPromptFS(DirFS(dir)), WithPromptDir("."), WithPromptWatch(true),
+		OnPromptReload(func(name string) { reloaded <- name }))
+
+	prompt := LookupPrompt(g, "greet")
+	if prompt == nil {
+		t.Fatal("want prompt 'greet' to be loaded")
+	}
+	if prompt.Template != "{{text}}" {
+		t.Fatalf("want initial template %q, got %q", "{{text}}", prompt.Template)
+	}
+
+	write(`---
+model: googleai/gemini-2.5-flash
+input:
+  schema:
+    text: string
+---
+Hello, {{text}}!`)
+
+	select {
+	case name := <-reloaded:
+		if name != "greet" {
+			t.Errorf("want reload callback for %q, got %q", "greet", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for prompt reload")
+	}
+
+	prompt = LookupPrompt(g, "greet")
+	if prompt == nil {
+		t.Fatal("want prompt 'greet' to still be registered after reload")
+	}
+	if prompt.Template != "Hello, {{text}}!" {
+		t.Errorf("want reloaded template %q, got %q", "Hello, {{text}}!", prompt.Template)
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package genkit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/core/x/streaming"
+)
+
+// lastEventIDHeader is the standard SSE reconnect header: a client
+// resubscribing after a dropped connection sends back the last `id:` value
+// it saw, so the server can replay only what it missed instead of the whole
+// stream. ndjson clients that want the same behavior can send it too; the
+// resume ID is carried as an `"id"` property on each envelope instead of an
+// `id:` line.
+const lastEventIDHeader = "Last-Event-ID"
+
+// streamIDHeader carries the opaque ID WithStreamManager assigns to a
+// streaming response, so a client can resubscribe to it after a dropped
+// connection by sending the same header back.
+const streamIDHeader = "X-Genkit-Stream-Id"
+
+// handlerConfig holds the options collected from a Handler/HandlerFunc
+// call's HandlerOption arguments.
+type handlerConfig struct {
+	contextProviders []core.ContextProvider
+	streamManager    streaming.StreamManager
+}
+
+// HandlerOption configures a Handler or HandlerFunc call.
+type HandlerOption func(*handlerConfig)
+
+// WithContextProviders runs each provider against the inbound request and
+// merges the resulting core.ActionContext values (later providers win on
+// key conflicts) into the context the flow runs with.
+func WithContextProviders(providers ...core.ContextProvider) HandlerOption {
+	return func(c *handlerConfig) {
+		c.contextProviders = append(c.contextProviders, providers...)
+	}
+}
+
+// WithStreamManager makes a streaming flow's response durable: chunks are
+// buffered in sm as they're produced, the response carries an
+// X-Genkit-Stream-Id header, and a later request with that header
+// resubscribes to the stream instead of re-running the flow.
+func WithStreamManager(sm streaming.StreamManager) HandlerOption {
+	return func(c *handlerConfig) {
+		c.streamManager = sm
+	}
+}
+
+// requestEnvelope is the `{"data": ...}` body Handler/HandlerFunc expect.
+type requestEnvelope struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type messageEnvelope struct {
+	Message json.RawMessage `json:"message"`
+}
+
+type resultEnvelope struct {
+	Result json.RawMessage `json:"result"`
+}
+
+type errorEnvelope struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+// Handler returns an http.HandlerFunc that runs action, writing its result
+// or error directly to the response. It's HandlerFunc with error handling
+// already applied; use HandlerFunc instead if the caller wants to handle
+// errors itself (for example, to log them with request-scoped fields).
+func Handler(action core.Action, opts ...HandlerOption) http.HandlerFunc {
+	hf := HandlerFunc(action, opts...)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := hf(w, r); err != nil {
+			writeError(w, err)
+		}
+	}
+}
+
+// HandlerFunc returns a function that runs action against an HTTP request:
+// decoding its `{"data": ...}` body, running any configured
+// WithContextProviders, and writing a `{"result": ...}` response — or, for a
+// streaming flow, an SSE or ndjson stream of `{"message": ...}` chunks
+// followed by a `{"result": ...}`/`{"error": ...}` terminal envelope,
+// negotiated from the request's Accept header.
+//
+// Errors that occur before the response has started being written (a
+// malformed body, a context provider rejecting the request, a non-streaming
+// flow returning an error) are returned rather than written, so Handler (or
+// a caller with its own error handling) decides how to report them. Once a
+// streaming response has started, a flow error is instead written into the
+// stream as an `{"error": ...}` envelope and this returns nil, since the
+// response's status code and framing are already committed.
+func HandlerFunc(action core.Action, opts ...HandlerOption) func(http.ResponseWriter, *http.Request) error {
+	cfg := &handlerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if streamID := r.Header.Get(streamIDHeader); streamID != "" && cfg.streamManager != nil {
+			return resumeStream(w, r, cfg, streamID)
+		}
+
+		var body requestEnvelope
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return err
+		}
+
+		ctx, err := withRequestActionContext(r.Context(), r, cfg, body.Data)
+		if err != nil {
+			return err
+		}
+
+		if sc, ok := action.(streamingCapable); ok && sc.streamingFlow() {
+			return runStreaming(ctx, w, r, action, cfg, body.Data)
+		}
+
+		out, err := action.RunJSON(ctx, body.Data, nil)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, resultEnvelope{Result: out})
+	}
+}
+
+// withRequestActionContext runs cfg's configured ContextProviders against
+// r/input and, if any are configured, returns a context carrying their
+// merged core.ActionContext.
+func withRequestActionContext(ctx context.Context, r *http.Request, cfg *handlerConfig, input json.RawMessage) (context.Context, error) {
+	if len(cfg.contextProviders) == 0 {
+		return ctx, nil
+	}
+
+	req := core.RequestData{
+		Method:  r.Method,
+		Headers: r.Header,
+		Input:   input,
+	}
+
+	merged := core.ActionContext{}
+	for _, provider := range cfg.contextProviders {
+		ac, err := provider(ctx, req)
+		if err != nil {
+			return ctx, err
+		}
+		for k, v := range ac {
+			merged[k] = v
+		}
+	}
+	return core.WithActionContext(ctx, merged), nil
+}
+
+// flusherFor returns a func that flushes w past net/http's own response
+// buffering, or nil if w doesn't implement http.Flusher (as a bare
+// http.ResponseWriter isn't guaranteed to, though the standard server's
+// always does).
+func flusherFor(w http.ResponseWriter) func() {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return nil
+	}
+	return f.Flush
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// writeError writes err as a `{"error": ...}` JSON response, mapping a
+// *core.GenkitError's Status to the equivalent HTTP status code. Any other
+// error (including a *core.PublicError, whose Error() is already the
+// caller-safe message) is reported as a generic 500.
+func writeError(w http.ResponseWriter, err error) {
+	var gerr *core.GenkitError
+	status := http.StatusInternalServerError
+	statusName := "INTERNAL_SERVER_ERROR"
+	if errors.As(err, &gerr) {
+		status = gerr.Status.HTTPStatus()
+		statusName = string(gerr.Status)
+	}
+	_ = writeJSON(w, status, errorEnvelope{Error: errorDetail{
+		Status:  statusName,
+		Message: err.Error(),
+	}})
+}
+
+// runStreaming runs a streaming action, writing each chunk and the terminal
+// result/error to w as it's produced. If cfg.streamManager is set, chunks
+// are also buffered there and the response carries an X-Genkit-Stream-Id
+// header so the client can resubscribe later. Chunks written here carry no
+// durable-resume ID; the manager numbers them from 1 in append order, and
+// only a resumed response to a request with a Last-Event-ID header frames
+// chunks with their IDs.
+func runStreaming(ctx context.Context, w http.ResponseWriter, r *http.Request, action core.Action, cfg *handlerConfig, input json.RawMessage) error {
+	format := negotiateStreamFormat(r)
+
+	var streamID string
+	if cfg.streamManager != nil {
+		id, err := cfg.streamManager.Create()
+		if err != nil {
+			return err
+		}
+		streamID = id
+		w.Header().Set(streamIDHeader, streamID)
+	}
+
+	w.Header().Set("Content-Type", contentTypeFor(format))
+	w.WriteHeader(http.StatusOK)
+
+	bw := bufio.NewWriter(w)
+	envW := newStreamEnvelopeWriter(bw, format, flusherFor(w))
+
+	cb := func(ctx context.Context, chunk json.RawMessage) error {
+		if cfg.streamManager != nil {
+			if err := cfg.streamManager.Append(streamID, chunk); err != nil {
+				return err
+			}
+		}
+		return envW.writeEnvelope(messageEnvelope{Message: chunk}, 0)
+	}
+
+	out, err := action.RunJSON(ctx, input, cb)
+	if err != nil {
+		var gerr *core.GenkitError
+		status, message, details := "INTERNAL_SERVER_ERROR", "stream flow error", err.Error()
+		if errors.As(err, &gerr) {
+			status, details = string(gerr.Status), gerr.Message
+		}
+		if cfg.streamManager != nil {
+			_ = cfg.streamManager.Fail(streamID, streaming.Failure{Status: status, Message: message, Details: details})
+		}
+		return envW.writeEnvelope(errorEnvelope{Error: errorDetail{Status: status, Message: message, Details: details}}, 0)
+	}
+
+	if cfg.streamManager != nil {
+		if err := cfg.streamManager.Complete(streamID, out); err != nil {
+			return err
+		}
+	}
+	return envW.writeEnvelope(resultEnvelope{Result: out}, 0)
+}
+
+// resumeStream serves a request carrying an X-Genkit-Stream-Id header by
+// replaying a previously buffered stream instead of running the flow again.
+// A streamID unknown to the manager (never created, or evicted) responds
+// 204 No Content.
+//
+// If the request also carries a Last-Event-ID header, only chunks strictly
+// after that ID are replayed, and each replayed chunk is framed with its
+// durable-resume ID (an `id:` field for SSE, an `"id"` property for ndjson)
+// so the client can reconnect again from wherever this response leaves off.
+// Without Last-Event-ID, every buffered chunk is replayed with no ID
+// framing, matching the framing of the original live response.
+func resumeStream(w http.ResponseWriter, r *http.Request, cfg *handlerConfig, streamID string) error {
+	format := negotiateStreamFormat(r)
+
+	var chunks []streaming.Chunk
+	var result *json.RawMessage
+	var failure *streaming.Failure
+	var done, ok, withIDs bool
+
+	if lastEventID := r.Header.Get(lastEventIDHeader); lastEventID != "" {
+		afterID, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("genkit: parsing %s header: %w", lastEventIDHeader, err)
+		}
+		withIDs = true
+		chunks, result, failure, done, ok = cfg.streamManager.ReadFrom(streamID, afterID)
+	} else {
+		chunks, result, failure, done, ok = cfg.streamManager.Load(streamID)
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", contentTypeFor(format))
+	w.WriteHeader(http.StatusOK)
+
+	bw := bufio.NewWriter(w)
+	envW := newStreamEnvelopeWriter(bw, format, flusherFor(w))
+
+	var lastID uint64
+	writeChunk := func(chunk streaming.Chunk) error {
+		id := uint64(0)
+		if withIDs {
+			id = chunk.ID
+		}
+		lastID = chunk.ID
+		return envW.writeEnvelope(messageEnvelope{Message: chunk.Data}, id)
+	}
+
+	for _, chunk := range chunks {
+		if err := writeChunk(chunk); err != nil {
+			return err
+		}
+	}
+
+	if !done {
+		done, result, failure, err := tailLiveStream(r.Context(), cfg.streamManager, streamID, lastID, writeChunk)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return nil
+		}
+		return writeTerminalEnvelope(envW, result, failure)
+	}
+	return writeTerminalEnvelope(envW, result, failure)
+}
+
+// subscribableStreamManager is implemented by a StreamManager that can tail
+// chunks appended by a producer on a different replica — currently only
+// *streaming.BackedStreamManager. A plain *streaming.InMemoryStreamManager
+// doesn't implement it, so resuming one of its streams that isn't done yet
+// replays only the chunks buffered so far and ends the response without a
+// terminal envelope.
+type subscribableStreamManager interface {
+	Subscribe(ctx context.Context, streamID string) (<-chan streaming.Chunk, func(), error)
+}
+
+// tailLiveStream follows a not-yet-done stream past what resumeStream
+// already replayed from the buffer, so a client that reconnects to a replica
+// other than the one producing the stream still sees it through to
+// completion instead of the connection just closing. If sm can't be
+// subscribed to (e.g. it's in-memory), the stream is reported not done and
+// resumeStream ends the response there.
+func tailLiveStream(ctx context.Context, sm streaming.StreamManager, streamID string, lastID uint64, writeChunk func(streaming.Chunk) error) (done bool, result *json.RawMessage, failure *streaming.Failure, err error) {
+	sub, ok := sm.(subscribableStreamManager)
+	if !ok {
+		return false, nil, nil, nil
+	}
+
+	live, unsubscribe, err := sub.Subscribe(ctx, streamID)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	defer unsubscribe()
+
+	// Subscribe only fans out chunks appended after it's called, so a chunk
+	// appended in the gap between resumeStream's initial Load/ReadFrom and
+	// this Subscribe call would otherwise be missed entirely; reading the
+	// buffer again here closes that gap, and dedup against lastID below keeps
+	// a chunk seen both here and live from being replayed twice.
+	if gapChunks, _, _, _, ok := sm.ReadFrom(streamID, lastID); ok {
+		for _, chunk := range gapChunks {
+			if err := writeChunk(chunk); err != nil {
+				return false, nil, nil, err
+			}
+			lastID = chunk.ID
+		}
+	}
+
+	liveClosed := false
+	for !liveClosed {
+		select {
+		case chunk, ok := <-live:
+			if !ok {
+				liveClosed = true
+				break
+			}
+			if chunk.ID <= lastID {
+				continue
+			}
+			if err := writeChunk(chunk); err != nil {
+				return false, nil, nil, err
+			}
+			lastID = chunk.ID
+		case <-ctx.Done():
+			return false, nil, nil, nil
+		}
+	}
+
+	// The channel closing means the backend marked the stream done; reload
+	// it for the terminal result/failure and any chunk that arrived between
+	// the last one we saw live and MarkDone.
+	gapChunks, result, failure, done, ok := sm.ReadFrom(streamID, lastID)
+	if !ok {
+		return false, nil, nil, nil
+	}
+	for _, chunk := range gapChunks {
+		if err := writeChunk(chunk); err != nil {
+			return false, nil, nil, err
+		}
+	}
+	return done, result, failure, nil
+}
+
+func writeTerminalEnvelope(envW *streamEnvelopeWriter, result *json.RawMessage, failure *streaming.Failure) error {
+	if failure != nil {
+		return envW.writeEnvelope(errorEnvelope{Error: errorDetail{
+			Status:  failure.Status,
+			Message: failure.Message,
+			Details: failure.Details,
+		}}, 0)
+	}
+	if result != nil {
+		return envW.writeEnvelope(resultEnvelope{Result: *result}, 0)
+	}
+	return nil
+}
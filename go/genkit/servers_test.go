@@ -18,13 +18,16 @@ package genkit
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/core/x/streaming"
@@ -431,6 +434,241 @@ data: {"result":"hello-end"}
 	})
 }
 
+// TestStreamingHandlerFunc_RealTransportFlushesProgressively exercises
+// Handler over a real TCP connection (httptest.NewServer, not
+// httptest.NewRecorder, which doesn't model net/http's own response
+// buffering). It asserts the first chunk arrives well before the flow
+// finishes, which only holds if runStreaming flushes the underlying
+// http.ResponseWriter via http.Flusher after every chunk instead of just the
+// local bufio.Writer.
+func TestStreamingHandlerFunc_RealTransportFlushesProgressively(t *testing.T) {
+	g := Init(context.Background())
+	const chunkDelay = 50 * time.Millisecond
+	const input = "ab"
+
+	flow := DefineStreamingFlow(g, "slowStreaming",
+		func(ctx context.Context, input string, cb func(context.Context, string) error) (string, error) {
+			for _, c := range input {
+				if err := cb(ctx, string(c)); err != nil {
+					return "", err
+				}
+				time.Sleep(chunkDelay)
+			}
+			return input + "-end", nil
+		})
+
+	server := httptest.NewServer(Handler(flow))
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"data":"ab"}`))
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	buf := make([]byte, 4096)
+	n, err := resp.Body.Read(buf)
+	firstReadElapsed := time.Since(start)
+	if err != nil && err != io.EOF {
+		t.Fatalf("reading first chunk: %v", err)
+	}
+	if n == 0 || !strings.Contains(string(buf[:n]), `"message":"a"`) {
+		t.Fatalf(`want first read to contain {"message":"a"}, got %q (n=%d)`, buf[:n], n)
+	}
+
+	fullStreamDuration := chunkDelay * time.Duration(len(input))
+	if firstReadElapsed >= fullStreamDuration {
+		t.Errorf("want the first chunk to arrive well before the stream finishes (%v), but the first read took %v — "+
+			"the response looks like it was buffered until the end instead of flushed per chunk", fullStreamDuration, firstReadElapsed)
+	}
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		t.Fatalf("draining rest of response: %v", err)
+	}
+	totalElapsed := time.Since(start)
+	if totalElapsed < fullStreamDuration {
+		t.Errorf("want the full stream to take at least %v (one sleep per chunk), took %v", fullStreamDuration, totalElapsed)
+	}
+}
+
+func TestStreamingHandlerFunc_NDJSON(t *testing.T) {
+	g := Init(context.Background())
+
+	streamingFlow := DefineStreamingFlow(g, "ndjsonStreaming",
+		func(ctx context.Context, input string, cb func(context.Context, string) error) (string, error) {
+			for _, c := range input {
+				if err := cb(ctx, string(c)); err != nil {
+					return "", err
+				}
+			}
+			return input + "-end", nil
+		})
+
+	t.Run("application/x-ndjson", func(t *testing.T) {
+		handlerFunc := HandlerFunc(streamingFlow)
+
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/x-ndjson")
+		w := httptest.NewRecorder()
+
+		err := handlerFunc(w, req)
+
+		if err != nil {
+			t.Errorf("want nil error, got %v", err)
+		}
+
+		resp := w.Result()
+		if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("want Content-Type application/x-ndjson, got %q", ct)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		expected := "{\"message\":\"h\"}\n{\"message\":\"i\"}\n{\"result\":\"hi-end\"}\n"
+		if string(body) != expected {
+			t.Errorf("want ndjson body:\n%q\n\nGot:\n%q", expected, string(body))
+		}
+	})
+
+	t.Run("application/jsonl is equivalent to application/x-ndjson", func(t *testing.T) {
+		handlerFunc := HandlerFunc(streamingFlow)
+
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"a"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/jsonl")
+		w := httptest.NewRecorder()
+
+		if err := handlerFunc(w, req); err != nil {
+			t.Errorf("want nil error, got %v", err)
+		}
+
+		body, _ := io.ReadAll(w.Result().Body)
+		expected := "{\"message\":\"a\"}\n{\"result\":\"a-end\"}\n"
+		if string(body) != expected {
+			t.Errorf("want ndjson body:\n%q\n\nGot:\n%q", expected, string(body))
+		}
+	})
+}
+
+func TestDurableStreamingHandlerFunc_NDJSON(t *testing.T) {
+	g := Init(context.Background())
+
+	streamingFlow := DefineStreamingFlow(g, "durableNdjsonStreaming",
+		func(ctx context.Context, input string, cb func(context.Context, string) error) (string, error) {
+			for _, c := range input {
+				if err := cb(ctx, string(c)); err != nil {
+					return "", err
+				}
+			}
+			return input + "-done", nil
+		})
+
+	t.Run("durable resume preserves ndjson framing", func(t *testing.T) {
+		sm := streaming.NewInMemoryStreamManager()
+		defer sm.Close()
+		handlerFunc := HandlerFunc(streamingFlow, WithStreamManager(sm))
+
+		req1 := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"ab"}`))
+		req1.Header.Set("Content-Type", "application/json")
+		req1.Header.Set("Accept", "application/x-ndjson")
+		w1 := httptest.NewRecorder()
+
+		if err := handlerFunc(w1, req1); err != nil {
+			t.Errorf("want nil error, got %v", err)
+		}
+
+		resp1 := w1.Result()
+		streamID := resp1.Header.Get("X-Genkit-Stream-Id")
+		if streamID == "" {
+			t.Fatal("want X-Genkit-Stream-Id header to be set")
+		}
+
+		req2 := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"ignored"}`))
+		req2.Header.Set("Content-Type", "application/json")
+		req2.Header.Set("Accept", "application/x-ndjson")
+		req2.Header.Set("X-Genkit-Stream-Id", streamID)
+		w2 := httptest.NewRecorder()
+
+		if err := handlerFunc(w2, req2); err != nil {
+			t.Errorf("want nil error, got %v", err)
+		}
+
+		body2, _ := io.ReadAll(w2.Result().Body)
+		expected := "{\"message\":\"a\"}\n{\"message\":\"b\"}\n{\"result\":\"ab-done\"}\n"
+		if string(body2) != expected {
+			t.Errorf("want replayed ndjson body:\n%q\n\nGot:\n%q", expected, string(body2))
+		}
+	})
+}
+
+func TestDurableStreamingHandlerFunc_LastEventID(t *testing.T) {
+	g := Init(context.Background())
+
+	streamingFlow := DefineStreamingFlow(g, "lastEventIDStreaming",
+		func(ctx context.Context, input string, cb func(context.Context, string) error) (string, error) {
+			for _, c := range input {
+				if err := cb(ctx, string(c)); err != nil {
+					return "", err
+				}
+			}
+			return input + "-done", nil
+		})
+
+	t.Run("resubscribe with Last-Event-ID replays only newer chunks, with ids", func(t *testing.T) {
+		sm := streaming.NewInMemoryStreamManager()
+		defer sm.Close()
+		handlerFunc := HandlerFunc(streamingFlow, WithStreamManager(sm))
+
+		req1 := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"abc"}`))
+		req1.Header.Set("Content-Type", "application/json")
+		req1.Header.Set("Accept", "text/event-stream")
+		w1 := httptest.NewRecorder()
+
+		if err := handlerFunc(w1, req1); err != nil {
+			t.Errorf("want nil error, got %v", err)
+		}
+
+		resp1 := w1.Result()
+		streamID := resp1.Header.Get("X-Genkit-Stream-Id")
+		if streamID == "" {
+			t.Fatal("want X-Genkit-Stream-Id header to be set")
+		}
+
+		// The client saw "a" (id 1) and "b" (id 2) before dropping
+		// connection; resubscribing with Last-Event-ID: 2 should replay only
+		// "c" onward, each framed with its durable-resume id.
+		req2 := httptest.NewRequest("POST", "/", strings.NewReader(`{"data":"ignored"}`))
+		req2.Header.Set("Content-Type", "application/json")
+		req2.Header.Set("Accept", "text/event-stream")
+		req2.Header.Set("X-Genkit-Stream-Id", streamID)
+		req2.Header.Set("Last-Event-ID", "2")
+		w2 := httptest.NewRecorder()
+
+		if err := handlerFunc(w2, req2); err != nil {
+			t.Errorf("want nil error, got %v", err)
+		}
+
+		body2, _ := io.ReadAll(w2.Result().Body)
+		expected := `id: 3
+data: {"message":"c"}
+
+data: {"result":"abc-done"}
+
+`
+		if string(body2) != expected {
+			t.Errorf("want replayed body:\n%q\n\nGot:\n%q", expected, string(body2))
+		}
+	})
+}
+
 func TestDurableStreamingHandlerFunc(t *testing.T) {
 	g := Init(context.Background())
 
@@ -556,3 +794,175 @@ data: {"result":"ab-done"}
 		}
 	})
 }
+
+// fakeSubscribeBackend is a minimal streaming.Backend, like fakeBackend in
+// the streaming package's own tests, except Subscribe actually fans out
+// chunks appended after subscription instead of returning an
+// already-closed channel — enough to exercise resumeStream's live-tail
+// path without a real Redis/PostgreSQL dependency.
+type fakeSubscribeBackend struct {
+	mu          sync.Mutex
+	nextID      map[string]uint64
+	chunks      map[string][]streaming.Chunk
+	done        map[string]bool
+	result      map[string]*json.RawMessage
+	failure     map[string]*streaming.Failure
+	subscribers map[string][]chan streaming.Chunk
+}
+
+func newFakeSubscribeBackend() *fakeSubscribeBackend {
+	return &fakeSubscribeBackend{
+		nextID:      map[string]uint64{},
+		chunks:      map[string][]streaming.Chunk{},
+		done:        map[string]bool{},
+		result:      map[string]*json.RawMessage{},
+		failure:     map[string]*streaming.Failure{},
+		subscribers: map[string][]chan streaming.Chunk{},
+	}
+}
+
+func (f *fakeSubscribeBackend) AppendChunk(ctx context.Context, streamID string, data json.RawMessage) (streaming.Chunk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID[streamID]++
+	chunk := streaming.Chunk{ID: f.nextID[streamID], Data: data}
+	f.chunks[streamID] = append(f.chunks[streamID], chunk)
+	for _, sub := range f.subscribers[streamID] {
+		sub <- chunk
+	}
+	return chunk, nil
+}
+
+func (f *fakeSubscribeBackend) LoadChunks(ctx context.Context, streamID string, afterID uint64) ([]streaming.Chunk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []streaming.Chunk
+	for _, c := range f.chunks[streamID] {
+		if c.ID > afterID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSubscribeBackend) MarkDone(ctx context.Context, streamID string, result *json.RawMessage, failure *streaming.Failure) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.done[streamID] = true
+	f.result[streamID] = result
+	f.failure[streamID] = failure
+	for _, sub := range f.subscribers[streamID] {
+		close(sub)
+	}
+	f.subscribers[streamID] = nil
+	return nil
+}
+
+func (f *fakeSubscribeBackend) LoadState(ctx context.Context, streamID string) (done bool, result *json.RawMessage, failure *streaming.Failure, ok bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok = f.nextID[streamID]
+	if !ok {
+		return false, nil, nil, false, nil
+	}
+	return f.done[streamID], f.result[streamID], f.failure[streamID], true, nil
+}
+
+func (f *fakeSubscribeBackend) SetTTL(ctx context.Context, streamID string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nextID[streamID]; !ok {
+		f.nextID[streamID] = 0
+	}
+	return nil
+}
+
+func (f *fakeSubscribeBackend) Subscribe(ctx context.Context, streamID string) (<-chan streaming.Chunk, func(), error) {
+	f.mu.Lock()
+	ch := make(chan streaming.Chunk, 16)
+	f.subscribers[streamID] = append(f.subscribers[streamID], ch)
+	f.mu.Unlock()
+
+	unsubscribe := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		subs := f.subscribers[streamID]
+		for i, sub := range subs {
+			if sub == ch {
+				f.subscribers[streamID] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+	return ch, unsubscribe, nil
+}
+
+func (f *fakeSubscribeBackend) Close() error { return nil }
+
+// TestDurableStreamingHandlerFunc_SubscribesToLiveTail exercises resumeStream
+// reconnecting to a stream that isn't done yet — the scenario where a client
+// reconnects to a replica other than the one producing the stream. It
+// asserts the response stays open and relays chunks appended, and the final
+// result, after the reconnect request was already in flight.
+func TestDurableStreamingHandlerFunc_SubscribesToLiveTail(t *testing.T) {
+	backend := newFakeSubscribeBackend()
+	sm := streaming.NewBackedStreamManager(backend)
+	defer sm.Close()
+
+	streamID, err := sm.Create()
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := sm.Append(streamID, json.RawMessage(`"a"`)); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	g := Init(context.Background())
+	dummyFlow := DefineFlow(g, "resumeLiveTailDummy", func(ctx context.Context, input string) (string, error) {
+		t.Fatal("resuming a stream must not re-run the flow")
+		return "", nil
+	})
+
+	server := httptest.NewServer(Handler(dummyFlow, WithStreamManager(sm)))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	req.Header.Set(streamIDHeader, streamID)
+	req.Header.Set("Accept", "text/event-stream")
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if err := sm.Append(streamID, json.RawMessage(`"b"`)); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := sm.Complete(streamID, json.RawMessage(`"done"`)); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+
+	expected := `data: {"message":"a"}
+
+data: {"message":"b"}
+
+data: {"result":"done"}
+
+`
+	if string(body) != expected {
+		t.Errorf("want body:\n%q\n\nGot:\n%q", expected, string(body))
+	}
+}